@@ -19,6 +19,8 @@ var (
 // Sentinel errors raised by inscriptions.
 var (
 	ErrP2PKHInscriptionNotFound = errors.New("no P2PKH inscription found")
+	ErrInscriptionNotFound      = errors.New("no inscription envelope found")
+	ErrInvalidInscription       = errors.New("malformed inscription envelope")
 )
 
 // Sentinel errors raised through encoding.
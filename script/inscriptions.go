@@ -13,3 +13,137 @@ type InscriptionArgs struct {
 type EnrichedInscriptionArgs struct {
 	OpReturnData [][]byte
 }
+
+// inscriptionPrefix is the protocol identifier pushed at the
+// start of an inscription envelope.
+const inscriptionPrefix = "ord"
+
+// Inscription envelope field tags. Only the content type is returned, the
+// other fields are tolerated and skipped.
+const (
+	inscriptionTagContentType = 1
+)
+
+// ParseInscription finds the first inscription envelope in the script:
+//
+// OP_FALSE OP_IF "ord" [<tag> <value> ...] OP_0 <data> ... OP_ENDIF
+//
+// The envelope may come before or after the rest of the locking script,
+// which is returned with the envelope removed. Field tags other than the
+// content type, such as pointer, parent and metadata, are skipped, and the
+// data may be split across several pushes. Any pushes following a top level
+// OP_RETURN outside the envelope are returned as enriched OP_RETURN data.
+func ParseInscription(s *Script) (*InscriptionArgs, error) {
+	if s == nil {
+		return nil, ErrInscriptionNotFound
+	}
+	chunks, err := DecodeScript(*s)
+	if err != nil && !containsOp(chunks, OpRETURN) {
+		return nil, err
+	}
+
+	offsets := make([]int, len(chunks)+1)
+	for i, chunk := range chunks {
+		offsets[i+1] = offsets[i] + chunkSize(chunk)
+	}
+
+	start := -1
+	for i := 0; i+2 < len(chunks); i++ {
+		if chunks[i].Op == OpRETURN {
+			break
+		}
+		if chunks[i].Op == OpFALSE && chunks[i+1].Op == OpIF &&
+			isPush(chunks[i+2].Op) && string(chunks[i+2].Data) == inscriptionPrefix {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, ErrInscriptionNotFound
+	}
+
+	ia := &InscriptionArgs{Data: []byte{}}
+	end := -1
+	for j := start + 3; j < len(chunks) && end < 0; {
+		switch chunk := chunks[j]; {
+		case chunk.Op == OpENDIF:
+			end = j
+		case chunk.Op == Op0:
+			// The body, which runs until the end of the envelope.
+			for j++; j < len(chunks) && chunks[j].Op != OpENDIF; j++ {
+				if !isPush(chunks[j].Op) {
+					return nil, ErrInvalidInscription
+				}
+				ia.Data = append(ia.Data, chunks[j].Data...)
+			}
+		default:
+			tag, ok := inscriptionTag(chunk)
+			if !ok || j+1 >= len(chunks) || !isPush(chunks[j+1].Op) {
+				return nil, ErrInvalidInscription
+			}
+			if tag == inscriptionTagContentType {
+				ia.ContentType = string(chunks[j+1].Data)
+			}
+			j += 2
+		}
+	}
+	if end < 0 {
+		return nil, ErrInvalidInscription
+	}
+
+	// Whatever is outside the envelope is the locking script, up to any OP_RETURN.
+	lockingScript := make([]byte, 0, len(*s)-(offsets[end+1]-offsets[start]))
+	lockingScript = append(lockingScript, (*s)[:offsets[start]]...)
+	for i := end + 1; i < len(chunks); i++ {
+		if chunks[i].Op == OpRETURN {
+			data := make([][]byte, 0, len(chunks)-i-1)
+			for _, chunk := range chunks[i+1:] {
+				data = append(data, chunk.Data)
+			}
+			ia.EnrichedArgs = &EnrichedInscriptionArgs{OpReturnData: data}
+			break
+		}
+		lockingScript = append(lockingScript, (*s)[offsets[i]:offsets[i+1]]...)
+	}
+	ia.LockingScript = NewFromBytes(lockingScript)
+
+	return ia, nil
+}
+
+// inscriptionTag returns the tag of an envelope field, which
+// is pushed either as a small integer or as a single byte.
+func inscriptionTag(chunk *ScriptChunk) (byte, bool) {
+	switch {
+	case chunk.Op >= Op1 && chunk.Op <= Op16:
+		return chunk.Op - Op1 + 1, true
+	case isPush(chunk.Op) && len(chunk.Data) == 1:
+		return chunk.Data[0], true
+	}
+	return 0, false
+}
+
+func isPush(op byte) bool {
+	return op <= OpPUSHDATA4
+}
+
+func containsOp(chunks []*ScriptChunk, op byte) bool {
+	for _, chunk := range chunks {
+		if chunk.Op == op {
+			return true
+		}
+	}
+	return false
+}
+
+// chunkSize returns the number of bytes the chunk occupies in the script.
+func chunkSize(chunk *ScriptChunk) int {
+	switch chunk.Op {
+	case OpPUSHDATA1:
+		return 2 + len(chunk.Data)
+	case OpPUSHDATA2:
+		return 3 + len(chunk.Data)
+	case OpPUSHDATA4:
+		return 5 + len(chunk.Data)
+	}
+	return 1 + len(chunk.Data)
+}
@@ -0,0 +1,312 @@
+package spv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter/errs"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/chaintracker"
+)
+
+var (
+	ErrNilTransaction      = errors.New("transaction is nil")
+	ErrInvalidMerkleRoot   = errors.New("merkle path root is not valid for its block height")
+	ErrMissingSourceOutput = errors.New("input has no source output")
+	ErrFeeTooLow           = errors.New("fee is too low")
+)
+
+// Report is the result of verifying a transaction and its ancestry.
+type Report struct {
+	TxID string
+	// Valid reports whether every transaction in the report is valid.
+	Valid bool
+	// Transactions holds a report for the verified transaction followed by
+	// its ancestors, breadth first. Ancestors of transactions proven by a
+	// merkle path are not verified.
+	Transactions []*TxReport
+	// UnprovenDepth is the length of the longest chain of transactions,
+	// starting with the verified transaction, which were verified by
+	// executing their scripts rather than by a merkle path. It is zero
+	// when the verified transaction itself is proven.
+	UnprovenDepth int
+}
+
+// TxReport is the result of verifying a single transaction.
+type TxReport struct {
+	TxID string
+	// Depth is the number of generations between the transaction and the
+	// verified transaction, which has depth zero.
+	Depth int
+	// Valid reports whether the transaction was proven by its merkle path,
+	// or passed every fee and script check. A transaction whose merkle path
+	// could not be checked because the chain tracker failed is not valid,
+	// as Verify would fail for it.
+	Valid bool
+	// MerkleProven reports whether the transaction was proven by its
+	// merkle path, in which case its fee and inputs are not checked.
+	MerkleProven bool
+	// MerklePathErr is why a transaction with a merkle path was not proven
+	// by it, either ErrInvalidMerkleRoot or the chain tracker's error. The
+	// transaction is then verified by executing its scripts, but remains
+	// invalid if the chain tracker failed.
+	MerklePathErr error
+	// Fee is nil when no fee model was given or the transaction is proven.
+	Fee *FeeReport
+	// Inputs is nil when the transaction is proven.
+	Inputs []InputReport
+
+	sources []string
+}
+
+// FeeReport is the result of checking a transaction's fee.
+type FeeReport struct {
+	Fee      uint64
+	Required uint64
+	// Err is ErrFeeTooLow, or why the fee could not be computed.
+	Err error
+}
+
+// InputReport is the result of executing an input's scripts.
+type InputReport struct {
+	Index          int
+	SourceTXID     string
+	SourceOutIndex uint32
+	// Err is why the input failed, nil if its scripts executed successfully.
+	Err error
+	// ScriptError is the interpreter's error when script execution failed.
+	ScriptError *errs.Error
+}
+
+// VerifyWithReport verifies a transaction and its ancestry like Verify,
+// but checks every transaction and input rather than stopping at the first
+// failure, and reports the outcome of each. An error is only returned when
+// verification could not be carried out.
+func VerifyWithReport(t *transaction.Transaction,
+	chainTracker chaintracker.ChainTracker,
+	feeModel transaction.FeeModel) (*Report, error) {
+	return VerifyWithReportCtx(context.Background(), t, chainTracker, feeModel)
+}
+
+// VerifyWithReportCtx is VerifyWithReport with a context, which is passed
+// to the chain tracker and checked before each transaction is verified.
+func VerifyWithReportCtx(ctx context.Context,
+	t *transaction.Transaction,
+	chainTracker chaintracker.ChainTracker,
+	feeModel transaction.FeeModel) (*Report, error) {
+	if t == nil {
+		return nil, ErrNilTransaction
+	}
+	if chainTracker == nil {
+		chainTracker = chaintracker.NewWhatsOnChain(chaintracker.MainNet, "")
+	}
+	chainTracker = chaintracker.BindContext(ctx, chaintracker.WithContext(chainTracker))
+
+	type queued struct {
+		tx    *transaction.Transaction
+		depth int
+	}
+	report := &Report{Valid: true}
+	reports := make(map[string]*TxReport)
+	txQueue := []queued{{tx: t}}
+	for len(txQueue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		q := txQueue[0]
+		txQueue = txQueue[1:]
+		txid := q.tx.TxID()
+		if _, ok := reports[txid.String()]; ok {
+			continue
+		}
+
+		r := verifyTx(q.tx, txid, chainTracker, feeModel)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r.Depth = q.depth
+		reports[r.TxID] = r
+		report.Transactions = append(report.Transactions, r)
+		report.Valid = report.Valid && r.Valid
+		if r.MerkleProven {
+			continue
+		}
+		for _, input := range q.tx.Inputs {
+			if input.SourceTransaction != nil {
+				txQueue = append(txQueue, queued{tx: input.SourceTransaction, depth: q.depth + 1})
+				r.sources = append(r.sources, input.SourceTransaction.TxID().String())
+			}
+		}
+	}
+
+	report.TxID = report.Transactions[0].TxID
+	report.UnprovenDepth = unprovenDepth(report.Transactions[0], reports, make(map[string]int))
+	return report, nil
+}
+
+func verifyTx(tx *transaction.Transaction,
+	txid *chainhash.Hash,
+	chainTracker chaintracker.ChainTracker,
+	feeModel transaction.FeeModel) *TxReport {
+	r := &TxReport{TxID: txid.String(), Valid: true}
+	if tx.MerklePath != nil {
+		if isValid, err := tx.MerklePath.Verify(txid, chainTracker); err != nil {
+			r.MerklePathErr = err
+			r.Valid = false
+		} else if isValid {
+			r.MerkleProven = true
+			return r
+		} else {
+			r.MerklePathErr = ErrInvalidMerkleRoot
+		}
+	}
+
+	if feeModel != nil {
+		r.Fee = checkFee(tx, feeModel)
+		if r.Fee.Err != nil {
+			r.Valid = false
+		}
+	}
+
+	r.Inputs = make([]InputReport, len(tx.Inputs))
+	for vin, input := range tx.Inputs {
+		ir := InputReport{Index: vin, SourceOutIndex: input.SourceTxOutIndex}
+		if input.SourceTXID != nil {
+			ir.SourceTXID = input.SourceTXID.String()
+		}
+		if sourceOutput := input.SourceTxOutput(); sourceOutput == nil {
+			ir.Err = ErrMissingSourceOutput
+		} else if err := interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, vin, sourceOutput),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		); err != nil {
+			ir.Err = err
+			var scriptErr errs.Error
+			if errors.As(err, &scriptErr) {
+				ir.ScriptError = &scriptErr
+			}
+		}
+		if ir.Err != nil {
+			r.Valid = false
+		}
+		r.Inputs[vin] = ir
+	}
+	return r
+}
+
+// checkFee compares the fee a transaction pays with the fee the model
+// requires for it.
+func checkFee(tx *transaction.Transaction, feeModel transaction.FeeModel) *FeeReport {
+	f := &FeeReport{}
+	totalIn, err := tx.TotalInputSatoshis()
+	if err != nil {
+		f.Err = err
+		return f
+	}
+	totalOut := tx.TotalOutputSatoshis()
+	if totalIn < totalOut {
+		f.Err = transaction.ErrInsufficientInputs
+		return f
+	}
+	f.Fee = totalIn - totalOut
+	if f.Required, err = feeModel.ComputeFee(tx); err != nil {
+		f.Err = err
+	} else if f.Fee < f.Required {
+		f.Err = ErrFeeTooLow
+	}
+	return f
+}
+
+func unprovenDepth(r *TxReport, reports map[string]*TxReport, memo map[string]int) int {
+	if r.MerkleProven {
+		return 0
+	}
+	if depth, ok := memo[r.TxID]; ok {
+		return depth
+	}
+	depth := 0
+	for _, txid := range r.sources {
+		depth = max(depth, unprovenDepth(reports[txid], reports, memo))
+	}
+	memo[r.TxID] = depth + 1
+	return depth + 1
+}
+
+// MerkleProven returns the txids of the transactions proven by a merkle path.
+func (r *Report) MerkleProven() []string {
+	var txids []string
+	for _, tr := range r.Transactions {
+		if tr.MerkleProven {
+			txids = append(txids, tr.TxID)
+		}
+	}
+	return txids
+}
+
+// ScriptVerified returns the txids of the valid transactions verified by
+// executing their scripts. Transactions which failed are listed by Failed.
+func (r *Report) ScriptVerified() []string {
+	var txids []string
+	for _, tr := range r.Transactions {
+		if !tr.MerkleProven && tr.Valid {
+			txids = append(txids, tr.TxID)
+		}
+	}
+	return txids
+}
+
+// Failed returns the txids of the transactions which are not valid.
+func (r *Report) Failed() []string {
+	var txids []string
+	for _, tr := range r.Transactions {
+		if !tr.Valid {
+			txids = append(txids, tr.TxID)
+		}
+	}
+	return txids
+}
+
+// Transaction returns the report for txid, or nil if it was not verified.
+func (r *Report) Transaction(txid string) *TxReport {
+	for _, tr := range r.Transactions {
+		if tr.TxID == txid {
+			return tr
+		}
+	}
+	return nil
+}
+
+// Err returns an error describing the first failure, or nil if the
+// transaction is valid.
+func (r *Report) Err() error {
+	for _, tr := range r.Transactions {
+		if err := tr.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Err returns an error describing why the transaction failed, or nil if it
+// is valid.
+func (r *TxReport) Err() error {
+	if r.Valid {
+		return nil
+	}
+	if r.MerklePathErr != nil && !errors.Is(r.MerklePathErr, ErrInvalidMerkleRoot) {
+		return fmt.Errorf("transaction %s merkle path: %w", r.TxID, r.MerklePathErr)
+	}
+	if r.Fee != nil && r.Fee.Err != nil {
+		return fmt.Errorf("transaction %s: fee %d, required %d: %w", r.TxID, r.Fee.Fee, r.Fee.Required, r.Fee.Err)
+	}
+	for _, in := range r.Inputs {
+		if in.Err != nil {
+			return fmt.Errorf("transaction %s input %d: %w", r.TxID, in.Index, in.Err)
+		}
+	}
+	return nil
+}
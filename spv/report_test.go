@@ -0,0 +1,158 @@
+package spv
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter/errs"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	feemodel "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
+	"github.com/stretchr/testify/require"
+)
+
+// rejectingHeadersClient accepts no merkle roots.
+type rejectingHeadersClient struct{}
+
+func (r *rejectingHeadersClient) IsValidRootForHeight(merkleRoot *chainhash.Hash, height uint32) (bool, error) {
+	return false, nil
+}
+
+// failingHeadersClient cannot check any merkle root.
+type failingHeadersClient struct{}
+
+var errTrackerDown = errors.New("chain tracker unavailable")
+
+func (f *failingHeadersClient) IsValidRootForHeight(merkleRoot *chainhash.Hash, height uint32) (bool, error) {
+	return false, errTrackerDown
+}
+
+func TestVerifyWithReport(t *testing.T) {
+	tx, err := transaction.NewTransactionFromBEEFHex(BRC62Hex)
+	require.NoError(t, err)
+	parentTxid := tx.Inputs[0].SourceTXID.String()
+
+	report, err := VerifyWithReport(tx, &GullibleHeadersClient{}, nil)
+	require.NoError(t, err)
+	require.True(t, report.Valid)
+	require.NoError(t, report.Err())
+	require.Equal(t, tx.TxID().String(), report.TxID)
+	require.Len(t, report.Transactions, 2)
+	require.Equal(t, []string{parentTxid}, report.MerkleProven())
+	require.Equal(t, []string{report.TxID}, report.ScriptVerified())
+	require.Empty(t, report.Failed())
+	require.Equal(t, 1, report.UnprovenDepth)
+
+	txReport := report.Transaction(report.TxID)
+	require.Equal(t, 0, txReport.Depth)
+	require.Nil(t, txReport.Fee)
+	require.Len(t, txReport.Inputs, 1)
+	require.Equal(t, parentTxid, txReport.Inputs[0].SourceTXID)
+	require.NoError(t, txReport.Inputs[0].Err)
+
+	parent := report.Transaction(parentTxid)
+	require.Equal(t, 1, parent.Depth)
+	require.True(t, parent.MerkleProven)
+	require.Nil(t, parent.Inputs)
+	require.Nil(t, report.Transaction("00"))
+}
+
+func TestVerifyWithReportUnprovenAncestry(t *testing.T) {
+	tx, err := transaction.NewTransactionFromBEEFHex(BRC62Hex)
+	require.NoError(t, err)
+
+	report, err := VerifyWithReport(tx, &rejectingHeadersClient{}, nil)
+	require.NoError(t, err)
+	require.False(t, report.Valid)
+	require.Empty(t, report.MerkleProven())
+	require.Equal(t, []string{report.TxID}, report.ScriptVerified())
+	require.Equal(t, 2, report.UnprovenDepth)
+
+	// The parent's merkle path is rejected and its own inputs cannot be
+	// checked, as the grandparent is not included.
+	parent := report.Transactions[1]
+	require.ErrorIs(t, parent.MerklePathErr, ErrInvalidMerkleRoot)
+	require.False(t, parent.Valid)
+	require.ErrorIs(t, parent.Inputs[0].Err, ErrMissingSourceOutput)
+	require.Nil(t, parent.Inputs[0].ScriptError)
+	require.ErrorIs(t, report.Err(), ErrMissingSourceOutput)
+	require.Contains(t, report.Err().Error(), parent.TxID)
+}
+
+func TestVerifyWithReportScriptFailure(t *testing.T) {
+	tx, err := transaction.NewTransactionFromBEEFHex(BRC62Hex)
+	require.NoError(t, err)
+	tx.Inputs[0].UnlockingScript = script.NewFromBytes([]byte{script.Op0, script.Op0})
+
+	report, err := VerifyWithReport(tx, &GullibleHeadersClient{}, nil)
+	require.NoError(t, err)
+	require.False(t, report.Valid)
+	require.Empty(t, report.ScriptVerified())
+	require.Equal(t, []string{report.TxID}, report.Failed())
+
+	input := report.Transactions[0].Inputs[0]
+	require.NotNil(t, input.ScriptError)
+	require.ErrorIs(t, report.Err(), input.Err)
+	require.True(t, errs.IsErrorCode(input.Err, input.ScriptError.ErrorCode))
+	require.True(t, report.Transactions[1].Valid)
+}
+
+func TestVerifyWithReportTrackerError(t *testing.T) {
+	tx, err := transaction.NewTransactionFromBEEFHex(BRC62Hex)
+	require.NoError(t, err)
+	parentTxid := tx.Inputs[0].SourceTXID.String()
+
+	// The parent cannot be proven without the chain tracker, so the report
+	// is invalid even though the parent's scripts are checked.
+	report, err := VerifyWithReport(tx, &failingHeadersClient{}, nil)
+	require.NoError(t, err)
+	require.False(t, report.Valid)
+	require.Contains(t, report.Failed(), parentTxid)
+
+	parent := report.Transaction(parentTxid)
+	require.ErrorIs(t, parent.MerklePathErr, errTrackerDown)
+	require.NotNil(t, parent.Inputs)
+	require.ErrorIs(t, report.Err(), errTrackerDown)
+	require.Contains(t, report.Err().Error(), parentTxid)
+}
+
+func TestVerifyWithReportFee(t *testing.T) {
+	tx, err := transaction.NewTransactionFromBEEFHex(BRC62Hex)
+	require.NoError(t, err)
+
+	report, err := VerifyWithReport(tx, &GullibleHeadersClient{}, &feemodel.SatoshisPerKilobyte{Satoshis: 1})
+	require.NoError(t, err)
+	require.True(t, report.Valid)
+	fee := report.Transactions[0].Fee
+	require.Equal(t, uint64(2), fee.Fee)
+	require.Equal(t, uint64(1), fee.Required)
+	require.NoError(t, fee.Err)
+	// Proven transactions are not charged a fee.
+	require.Nil(t, report.Transactions[1].Fee)
+
+	report, err = VerifyWithReport(tx, &GullibleHeadersClient{}, &feemodel.SatoshisPerKilobyte{Satoshis: 10})
+	require.NoError(t, err)
+	require.False(t, report.Valid)
+	fee = report.Transactions[0].Fee
+	require.Equal(t, uint64(10), fee.Required)
+	require.ErrorIs(t, fee.Err, ErrFeeTooLow)
+	require.ErrorIs(t, report.Err(), ErrFeeTooLow)
+	// The inputs are still checked.
+	require.NoError(t, report.Transactions[0].Inputs[0].Err)
+}
+
+func TestVerifyWithReportCtx(t *testing.T) {
+	tx, err := transaction.NewTransactionFromBEEFHex(BRC62Hex)
+	require.NoError(t, err)
+
+	_, err = VerifyWithReport(nil, &GullibleHeadersClient{}, nil)
+	require.ErrorIs(t, err, ErrNilTransaction)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := VerifyWithReportCtx(ctx, tx, &GullibleHeadersClient{}, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, report)
+}
@@ -1,6 +1,7 @@
 package spv
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/bsv-blockchain/go-sdk/script/interpreter"
@@ -9,6 +10,16 @@ import (
 )
 
 func Verify(t *transaction.Transaction,
+	chainTracker chaintracker.ChainTracker,
+	feeModel transaction.FeeModel) (bool, error) {
+	return VerifyCtx(context.Background(), t, chainTracker, feeModel)
+}
+
+// VerifyCtx is Verify with a context, which is passed to the chain tracker
+// and checked before each transaction is verified, so that verification
+// stops once ctx is done.
+func VerifyCtx(ctx context.Context,
+	t *transaction.Transaction,
 	chainTracker chaintracker.ChainTracker,
 	feeModel transaction.FeeModel) (bool, error) {
 	verifiedTxids := make(map[string]struct{})
@@ -16,8 +27,12 @@ func Verify(t *transaction.Transaction,
 	if chainTracker == nil {
 		chainTracker = chaintracker.NewWhatsOnChain(chaintracker.MainNet, "")
 	}
+	chainTracker = chaintracker.BindContext(ctx, chaintracker.WithContext(chainTracker))
 
 	for len(txQueue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
 		tx := txQueue[0]
 		txQueue = txQueue[1:]
 		txid := tx.TxID()
@@ -71,7 +86,6 @@ func Verify(t *transaction.Transaction,
 				interpreter.WithForkID(),
 				interpreter.WithAfterGenesis(),
 			); err != nil {
-				fmt.Println(err)
 				return false, err
 			}
 		}
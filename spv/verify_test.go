@@ -3,6 +3,7 @@
 package spv
 
 import (
+	"context"
 	"encoding/base64"
 	"testing"
 
@@ -58,3 +59,17 @@ func TestSPVVerifyWithInsufficientFee(t *testing.T) {
 	require.Contains(t, err.Error(), "fee is too low")
 	require.False(t, verified)
 }
+
+func TestSPVVerifyCtx(t *testing.T) {
+	tx, err := transaction.NewTransactionFromBEEFHex(BRC62Hex)
+	require.NoError(t, err)
+	verified, err := VerifyCtx(context.Background(), tx, &GullibleHeadersClient{}, nil)
+	require.NoError(t, err)
+	require.True(t, verified)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	verified, err = VerifyCtx(ctx, tx, &GullibleHeadersClient{}, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, verified)
+}
@@ -1,5 +1,7 @@
 package transaction
 
+import "context"
+
 type BroadcastSuccess struct {
 	Txid    string `json:"txid"`
 	Message string `json:"message"`
@@ -18,6 +20,54 @@ type Broadcaster interface {
 	Broadcast(tx *Transaction) (*BroadcastSuccess, *BroadcastFailure)
 }
 
+// BroadcasterCtx is a Broadcaster whose requests can be cancelled or given
+// a deadline through a context.
+type BroadcasterCtx interface {
+	BroadcastCtx(ctx context.Context, tx *Transaction) (*BroadcastSuccess, *BroadcastFailure)
+}
+
 func (t *Transaction) Broadcast(b Broadcaster) (*BroadcastSuccess, *BroadcastFailure) {
 	return b.Broadcast(t)
 }
+
+// BroadcastCtx broadcasts the transaction with a context for the request.
+func (t *Transaction) BroadcastCtx(ctx context.Context, b BroadcasterCtx) (*BroadcastSuccess, *BroadcastFailure) {
+	return b.BroadcastCtx(ctx, t)
+}
+
+// BroadcasterWithContext returns b as a BroadcasterCtx. Broadcasters which
+// already implement BroadcasterCtx are returned as they are. Others are
+// only checked for a done context before broadcasting, as a broadcast in
+// progress cannot be abandoned without losing its result.
+func BroadcasterWithContext(b Broadcaster) BroadcasterCtx {
+	if bc, ok := b.(BroadcasterCtx); ok {
+		return bc
+	}
+	return broadcasterAdapter{b}
+}
+
+type broadcasterAdapter struct {
+	broadcaster Broadcaster
+}
+
+func (a broadcasterAdapter) BroadcastCtx(ctx context.Context, tx *Transaction) (*BroadcastSuccess, *BroadcastFailure) {
+	if err := ctx.Err(); err != nil {
+		return nil, &BroadcastFailure{Code: "500", Description: err.Error()}
+	}
+	return a.broadcaster.Broadcast(tx)
+}
+
+// BindBroadcasterContext returns a Broadcaster which calls b with ctx, for
+// passing a cancellable broadcaster to functions which take a Broadcaster.
+func BindBroadcasterContext(ctx context.Context, b BroadcasterCtx) Broadcaster {
+	return boundBroadcaster{ctx: ctx, broadcaster: b}
+}
+
+type boundBroadcaster struct {
+	ctx         context.Context
+	broadcaster BroadcasterCtx
+}
+
+func (b boundBroadcaster) Broadcast(tx *Transaction) (*BroadcastSuccess, *BroadcastFailure) {
+	return b.broadcaster.BroadcastCtx(b.ctx, tx)
+}
@@ -60,6 +60,11 @@ type ArcResponse struct {
 }
 
 func (a *Arc) Broadcast(t *transaction.Transaction) (*transaction.BroadcastSuccess, *transaction.BroadcastFailure) {
+	return a.BroadcastCtx(context.Background(), t)
+}
+
+// BroadcastCtx is Broadcast with a context for the request.
+func (a *Arc) BroadcastCtx(ctx context.Context, t *transaction.Transaction) (*transaction.BroadcastSuccess, *transaction.BroadcastFailure) {
 	var buf *bytes.Buffer
 	for _, input := range t.Inputs {
 		if input.SourceTxOutput() == nil {
@@ -78,7 +83,6 @@ func (a *Arc) Broadcast(t *transaction.Transaction) (*transaction.BroadcastSucce
 		}
 	}
 
-	ctx := context.Background()
 	req, err := http.NewRequestWithContext(
 		ctx,
 		"POST",
@@ -188,7 +192,11 @@ func (a *Arc) Broadcast(t *transaction.Transaction) (*transaction.BroadcastSucce
 }
 
 func (a *Arc) Status(txid string) (*ArcResponse, error) {
-	ctx := context.Background()
+	return a.StatusCtx(context.Background(), txid)
+}
+
+// StatusCtx is Status with a context for the request.
+func (a *Arc) StatusCtx(ctx context.Context, txid string) (*ArcResponse, error) {
 	req, err := http.NewRequestWithContext(
 		ctx,
 		"GET",
@@ -25,11 +25,18 @@ type TAALBroadcast struct {
 func (b *TAALBroadcast) Broadcast(t *transaction.Transaction) (
 	*transaction.BroadcastSuccess,
 	*transaction.BroadcastFailure,
+) {
+	return b.BroadcastCtx(context.Background(), t)
+}
+
+// BroadcastCtx is Broadcast with a context for the request.
+func (b *TAALBroadcast) BroadcastCtx(ctx context.Context, t *transaction.Transaction) (
+	*transaction.BroadcastSuccess,
+	*transaction.BroadcastFailure,
 ) {
 	buf := bytes.NewBuffer(t.Bytes())
 	url := "https://api.taal.com/api/v1/broadcast"
 
-	ctx := context.Background()
 	req, err := http.NewRequestWithContext(
 		ctx,
 		"POST",
@@ -31,6 +31,14 @@ type WhatsOnChain struct {
 func (b *WhatsOnChain) Broadcast(t *transaction.Transaction) (
 	*transaction.BroadcastSuccess,
 	*transaction.BroadcastFailure,
+) {
+	return b.BroadcastCtx(context.Background(), t)
+}
+
+// BroadcastCtx is Broadcast with a context for the request.
+func (b *WhatsOnChain) BroadcastCtx(ctx context.Context, t *transaction.Transaction) (
+	*transaction.BroadcastSuccess,
+	*transaction.BroadcastFailure,
 ) {
 	if t == nil {
 		return nil, &transaction.BroadcastFailure{
@@ -53,7 +61,6 @@ func (b *WhatsOnChain) Broadcast(t *transaction.Transaction) (
 		}
 	} else {
 		url := fmt.Sprintf("https://api.whatsonchain.com/v1/bsv/%s/tx/raw", b.Network)
-		ctx := context.Background()
 		req, err := http.NewRequestWithContext(
 			ctx,
 			"POST",
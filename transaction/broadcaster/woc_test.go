@@ -1,6 +1,7 @@
 package broadcaster
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -270,3 +271,68 @@ func TestWhatsOnChainBroadcastTestnet(t *testing.T) {
 	require.NotNil(t, success)
 	require.Nil(t, failure)
 }
+
+// MockContextClient fails requests whose context is done and passes the
+// rest on to Client.
+type MockContextClient struct {
+	Client HTTPClient
+}
+
+func (m *MockContextClient) Do(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	return m.Client.Do(req)
+}
+
+// legacyBroadcaster only implements the Broadcaster interface.
+type legacyBroadcaster struct {
+	calls int
+}
+
+func (l *legacyBroadcaster) Broadcast(tx *transaction.Transaction) (*transaction.BroadcastSuccess, *transaction.BroadcastFailure) {
+	l.calls++
+	return &transaction.BroadcastSuccess{Txid: tx.TxID().String()}, nil
+}
+
+func TestBroadcastCtx(t *testing.T) {
+	tx, err := transaction.NewTransactionFromHex(testTxHex)
+	require.NoError(t, err)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	broadcasters := map[string]transaction.BroadcasterCtx{
+		"arc":  &Arc{ApiUrl: "https://arc.example.com", Client: &MockContextClient{Client: &MockArcSuccessClient{}}},
+		"taal": &TAALBroadcast{Client: &MockContextClient{Client: &MockTAALSuccessClient{}}},
+		"woc":  &WhatsOnChain{Network: WOCMainnet, Client: &MockContextClient{Client: &MockSuccessClient{}}},
+	}
+	for name, b := range broadcasters {
+		t.Run(name, func(t *testing.T) {
+			success, failure := tx.BroadcastCtx(context.Background(), b)
+			require.Nil(t, failure)
+			require.Equal(t, tx.TxID().String(), success.Txid)
+
+			success, failure = tx.BroadcastCtx(cancelled, b)
+			require.Nil(t, success)
+			require.Contains(t, failure.Description, context.Canceled.Error())
+
+			// Binding a context gives a Broadcaster for the old signature.
+			success, failure = tx.Broadcast(transaction.BindBroadcasterContext(cancelled, b))
+			require.Nil(t, success)
+			require.Contains(t, failure.Description, context.Canceled.Error())
+		})
+	}
+
+	legacy := &legacyBroadcaster{}
+	b := transaction.BroadcasterWithContext(legacy)
+	success, failure := b.BroadcastCtx(context.Background(), tx)
+	require.Nil(t, failure)
+	require.NotNil(t, success)
+	_, failure = b.BroadcastCtx(cancelled, tx)
+	require.Equal(t, context.Canceled.Error(), failure.Description)
+	require.Equal(t, 1, legacy.calls)
+
+	arc := broadcasters["arc"]
+	require.Same(t, arc, transaction.BroadcasterWithContext(arc.(transaction.Broadcaster)))
+}
@@ -0,0 +1,361 @@
+// Package bsv20 builds and parses BSV-20 and BSV-21 fungible token
+// inscriptions.
+//
+// Both protocols are JSON inscriptions with the application/bsv-20 content
+// type. BSV-20 tokens are identified by a ticker which is deployed once and
+// then minted in batches. BSV-21 tokens are deployed and minted in a single
+// deploy+mint inscription and identified by the outpoint of that
+// inscription, written as <txid>_<vout>. Tokens of both kinds move between
+// owners with transfer inscriptions.
+//
+// Amounts are always in the token's smallest unit, decimals only affect
+// how they are displayed.
+//
+// See https://docs.1satordinals.com/fungible-tokens/bsv-20 and
+// https://docs.1satordinals.com/fungible-tokens/bsv-21.
+package bsv20
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+const (
+	// ContentType is the content type of token inscriptions.
+	ContentType = "application/bsv-20"
+	// Protocol is the value of the p field of token inscriptions.
+	Protocol = "bsv-20"
+	// MaxDecimals is the largest number of decimals a token can have.
+	MaxDecimals = 18
+	// MaxTickLength is the maximum length of a BSV-20 ticker in characters.
+	MaxTickLength = 4
+)
+
+var (
+	ErrNotBSV20           = errors.New("inscription is not a bsv-20 token inscription")
+	ErrInvalidOp          = errors.New("invalid token operation")
+	ErrInvalidTick        = errors.New("tick must be between 1 and 4 characters")
+	ErrInvalidID          = errors.New("token id must be <txid>_<vout>")
+	ErrInvalidAmount      = errors.New("token amount must be a positive integer")
+	ErrInvalidMax         = errors.New("token max supply must be a positive integer")
+	ErrInvalidLimit       = errors.New("mint limit must not exceed the max supply")
+	ErrInvalidDecimals    = errors.New("token decimals must be at most 18")
+	ErrUnexpectedField    = errors.New("field is not valid for the token operation")
+	ErrNoRecipients       = errors.New("transfer has no recipients")
+	ErrTokenMismatch      = errors.New("inputs are not all the same token")
+	ErrNotTransferable    = errors.New("input does not hold transferable tokens")
+	ErrInsufficientTokens = errors.New("inputs hold fewer tokens than the transfer amount")
+	ErrNotOneSatoshi      = errors.New("token input does not hold exactly 1 satoshi")
+)
+
+// Op is a token operation.
+type Op string
+
+const (
+	// OpDeploy deploys a BSV-20 ticker.
+	OpDeploy Op = "deploy"
+	// OpMint mints tokens of a deployed BSV-20 ticker.
+	OpMint Op = "mint"
+	// OpDeployMint deploys a BSV-21 token and mints its whole supply.
+	OpDeployMint Op = "deploy+mint"
+	// OpTransfer transfers BSV-20 or BSV-21 tokens.
+	OpTransfer Op = "transfer"
+)
+
+// Inscription is a BSV-20 or BSV-21 token inscription. Which fields are
+// used depends on the operation, see Validate.
+type Inscription struct {
+	Op Op
+	// Tick is the ticker of a BSV-20 token.
+	Tick string
+	// ID is the id of a BSV-21 token.
+	ID string
+	// Symbol is the optional display symbol of a BSV-21 token.
+	Symbol string
+	// Icon is the optional outpoint of the icon of a BSV-21 token.
+	Icon string
+	// Amount is the number of tokens minted or transferred.
+	Amount uint64
+	// Max is the max supply and Limit the optional mint limit of a BSV-20 deploy.
+	Max   uint64
+	Limit uint64
+	// Decimals is the number of decimals of a deployed token.
+	Decimals uint8
+}
+
+// jsonInscription is the wire format, which encodes numbers as strings.
+type jsonInscription struct {
+	P    string `json:"p"`
+	Op   Op     `json:"op"`
+	Tick string `json:"tick,omitempty"`
+	ID   string `json:"id,omitempty"`
+	Sym  string `json:"sym,omitempty"`
+	Icon string `json:"icon,omitempty"`
+	Amt  string `json:"amt,omitempty"`
+	Max  string `json:"max,omitempty"`
+	Lim  string `json:"lim,omitempty"`
+	Dec  string `json:"dec,omitempty"`
+}
+
+// Deploy returns a BSV-20 deploy inscription for tick. A limit of zero
+// leaves minting unlimited up to the max supply.
+func Deploy(tick string, supply, limit uint64, decimals uint8) (*Inscription, error) {
+	i := &Inscription{Op: OpDeploy, Tick: tick, Max: supply, Limit: limit, Decimals: decimals}
+	return i, i.Validate()
+}
+
+// Mint returns a BSV-20 mint inscription.
+func Mint(tick string, amount uint64) (*Inscription, error) {
+	i := &Inscription{Op: OpMint, Tick: tick, Amount: amount}
+	return i, i.Validate()
+}
+
+// DeployMint returns a BSV-21 deploy+mint inscription which creates a
+// token with a supply of amount. The symbol and icon are optional.
+func DeployMint(symbol string, amount uint64, decimals uint8, icon string) (*Inscription, error) {
+	i := &Inscription{Op: OpDeployMint, Symbol: symbol, Amount: amount, Decimals: decimals, Icon: icon}
+	return i, i.Validate()
+}
+
+// Transfer returns a transfer inscription for either a BSV-20 tick or a
+// BSV-21 token id.
+func Transfer(token string, amount uint64) (*Inscription, error) {
+	i := &Inscription{Op: OpTransfer, Amount: amount}
+	if IsID(token) {
+		i.ID = token
+	} else {
+		i.Tick = token
+	}
+	return i, i.Validate()
+}
+
+// ID returns the id of the BSV-21 token deployed by the deploy+mint
+// inscription at the given outpoint.
+func ID(txid *chainhash.Hash, vout uint32) string {
+	return fmt.Sprintf("%s_%d", txid.String(), vout)
+}
+
+// IsID reports whether token is a BSV-21 token id rather than a BSV-20 tick.
+func IsID(token string) bool {
+	txid, vout, ok := strings.Cut(token, "_")
+	if !ok || len(txid) != chainhash.MaxHashStringSize {
+		return false
+	}
+	if _, err := hex.DecodeString(txid); err != nil {
+		return false
+	}
+	_, err := strconv.ParseUint(vout, 10, 32)
+	return err == nil
+}
+
+// Token returns the tick or id identifying the token.
+func (i *Inscription) Token() string {
+	if i.ID != "" {
+		return i.ID
+	}
+	return i.Tick
+}
+
+// Validate checks the inscription has the fields required by its
+// operation, and no others.
+func (i *Inscription) Validate() error {
+	if i.Decimals > MaxDecimals {
+		return ErrInvalidDecimals
+	}
+
+	switch i.Op {
+	case OpDeploy:
+		if err := validateTick(i.Tick); err != nil {
+			return err
+		}
+		if i.Max == 0 {
+			return ErrInvalidMax
+		}
+		if i.Limit > i.Max {
+			return ErrInvalidLimit
+		}
+		if i.ID != "" || i.Symbol != "" || i.Icon != "" || i.Amount != 0 {
+			return ErrUnexpectedField
+		}
+
+	case OpMint:
+		if err := validateTick(i.Tick); err != nil {
+			return err
+		}
+		if i.Amount == 0 {
+			return ErrInvalidAmount
+		}
+		if i.ID != "" || i.Symbol != "" || i.Icon != "" || i.Max != 0 || i.Limit != 0 || i.Decimals != 0 {
+			return ErrUnexpectedField
+		}
+
+	case OpDeployMint:
+		if i.Amount == 0 {
+			return ErrInvalidAmount
+		}
+		if i.Tick != "" || i.ID != "" || i.Max != 0 || i.Limit != 0 {
+			return ErrUnexpectedField
+		}
+
+	case OpTransfer:
+		switch {
+		case i.ID != "" && i.Tick != "":
+			return ErrUnexpectedField
+		case i.ID != "":
+			if !IsID(i.ID) {
+				return ErrInvalidID
+			}
+		default:
+			if err := validateTick(i.Tick); err != nil {
+				return err
+			}
+		}
+		if i.Amount == 0 {
+			return ErrInvalidAmount
+		}
+		if i.Symbol != "" || i.Icon != "" || i.Max != 0 || i.Limit != 0 || i.Decimals != 0 {
+			return ErrUnexpectedField
+		}
+
+	default:
+		return ErrInvalidOp
+	}
+	return nil
+}
+
+func validateTick(tick string) error {
+	if n := utf8.RuneCountInString(tick); n == 0 || n > MaxTickLength {
+		return ErrInvalidTick
+	}
+	return nil
+}
+
+// MarshalJSON encodes the inscription in its wire format.
+func (i *Inscription) MarshalJSON() ([]byte, error) {
+	j := jsonInscription{P: Protocol, Op: i.Op, Tick: i.Tick, ID: i.ID, Sym: i.Symbol, Icon: i.Icon}
+	if i.Amount != 0 {
+		j.Amt = strconv.FormatUint(i.Amount, 10)
+	}
+	if i.Max != 0 {
+		j.Max = strconv.FormatUint(i.Max, 10)
+	}
+	if i.Limit != 0 {
+		j.Lim = strconv.FormatUint(i.Limit, 10)
+	}
+	if i.Op == OpDeploy || i.Op == OpDeployMint {
+		j.Dec = strconv.FormatUint(uint64(i.Decimals), 10)
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes the inscription from its wire format. It does not
+// validate the inscription.
+func (i *Inscription) UnmarshalJSON(b []byte) error {
+	var j jsonInscription
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.P != Protocol {
+		return ErrNotBSV20
+	}
+	parsed := Inscription{Op: j.Op, Tick: j.Tick, ID: j.ID, Symbol: j.Sym, Icon: j.Icon}
+	var err error
+	if parsed.Amount, err = parseAmount(j.Amt, ErrInvalidAmount); err != nil {
+		return err
+	}
+	if parsed.Max, err = parseAmount(j.Max, ErrInvalidMax); err != nil {
+		return err
+	}
+	if parsed.Limit, err = parseAmount(j.Lim, ErrInvalidLimit); err != nil {
+		return err
+	}
+	if j.Dec != "" {
+		dec, err := strconv.ParseUint(j.Dec, 10, 8)
+		if err != nil {
+			return ErrInvalidDecimals
+		}
+		parsed.Decimals = uint8(dec)
+	}
+	*i = parsed
+	return nil
+}
+
+// parseAmount parses an amount, which must be a plain decimal integer.
+func parseAmount(s string, invalid error) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if s[0] == '+' || (len(s) > 1 && s[0] == '0') {
+		return 0, invalid
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	return v, nil
+}
+
+// Decode parses and validates the JSON body of a token inscription.
+func Decode(b []byte) (*Inscription, error) {
+	i := &Inscription{}
+	if err := json.Unmarshal(b, i); err != nil {
+		return nil, err
+	}
+	if err := i.Validate(); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// Parse decodes the token inscription of a locking script. The remaining
+// locking script, which controls the tokens, is also returned.
+func Parse(s *script.Script) (*Inscription, *script.Script, error) {
+	ia, err := script.ParseInscription(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ia.ContentType != ContentType {
+		return nil, nil, ErrNotBSV20
+	}
+	i, err := Decode(ia.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return i, ia.LockingScript, nil
+}
+
+// InscriptionArgs returns the arguments to inscribe the token inscription
+// on an output locked with lockingScript.
+func (i *Inscription) InscriptionArgs(lockingScript *script.Script) (*script.InscriptionArgs, error) {
+	if err := i.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+	return &script.InscriptionArgs{
+		LockingScript: lockingScript,
+		Data:          data,
+		ContentType:   ContentType,
+	}, nil
+}
+
+// Inscribe adds a 1 satoshi output with the token inscription to the
+// transaction, locked with lockingScript.
+func (i *Inscription) Inscribe(tx *transaction.Transaction, lockingScript *script.Script) error {
+	ia, err := i.InscriptionArgs(lockingScript)
+	if err != nil {
+		return err
+	}
+	return tx.Inscribe(ia)
+}
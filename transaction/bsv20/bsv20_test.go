@@ -0,0 +1,142 @@
+package bsv20_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/bsv20"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/require"
+)
+
+func newLock(t *testing.T) *script.Script {
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(key.PubKey(), true)
+	require.NoError(t, err)
+	s, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	return s
+}
+
+func TestInscriptionJSON(t *testing.T) {
+	deploy, err := bsv20.Deploy("ordi", 21000000, 1000, 8)
+	require.NoError(t, err)
+	b, err := json.Marshal(deploy)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"p":"bsv-20","op":"deploy","tick":"ordi","max":"21000000","lim":"1000","dec":"8"}`, string(b))
+
+	mint, err := bsv20.Mint("ordi", 1000)
+	require.NoError(t, err)
+	b, err = json.Marshal(mint)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"p":"bsv-20","op":"mint","tick":"ordi","amt":"1000"}`, string(b))
+
+	deployMint, err := bsv20.DeployMint("GOLD", 1000000, 2, "")
+	require.NoError(t, err)
+	b, err = json.Marshal(deployMint)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"p":"bsv-20","op":"deploy+mint","sym":"GOLD","amt":"1000000","dec":"2"}`, string(b))
+
+	id := "45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d_0"
+	transfer, err := bsv20.Transfer(id, 50)
+	require.NoError(t, err)
+	b, err = json.Marshal(transfer)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"p":"bsv-20","op":"transfer","id":"`+id+`","amt":"50"}`, string(b))
+
+	decoded, err := bsv20.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, transfer, decoded)
+}
+
+func TestValidation(t *testing.T) {
+	_, err := bsv20.Deploy("toolong", 100, 0, 0)
+	require.ErrorIs(t, err, bsv20.ErrInvalidTick)
+	_, err = bsv20.Deploy("ordi", 100, 200, 0)
+	require.ErrorIs(t, err, bsv20.ErrInvalidLimit)
+	_, err = bsv20.Deploy("ordi", 100, 0, 19)
+	require.ErrorIs(t, err, bsv20.ErrInvalidDecimals)
+	_, err = bsv20.Mint("ordi", 0)
+	require.ErrorIs(t, err, bsv20.ErrInvalidAmount)
+
+	for body, expected := range map[string]error{
+		`{"p":"bsv-20","op":"mint","tick":"ordi","amt":"-1"}`:                      bsv20.ErrInvalidAmount,
+		`{"p":"bsv-20","op":"mint","tick":"ordi","amt":"1.5"}`:                     bsv20.ErrInvalidAmount,
+		`{"p":"bsv-20","op":"mint","tick":"ordi","amt":"0100"}`:                    bsv20.ErrInvalidAmount,
+		`{"p":"bsv-20","op":"mint","tick":"ordi","amt":"18446744073709551616"}`:    bsv20.ErrInvalidAmount,
+		`{"p":"bsv-20","op":"burn","tick":"ordi","amt":"1"}`:                       bsv20.ErrInvalidOp,
+		`{"p":"brc-20","op":"mint","tick":"ordi","amt":"1"}`:                       bsv20.ErrNotBSV20,
+		`{"p":"bsv-20","op":"transfer","id":"abc_0","amt":"1"}`:                    bsv20.ErrInvalidID,
+		`{"p":"bsv-20","op":"mint","tick":"ordi","amt":"1","max":"5"}`:             bsv20.ErrUnexpectedField,
+		`{"p":"bsv-20","op":"deploy+mint","tick":"ordi","amt":"1","dec":"0"}`:      bsv20.ErrUnexpectedField,
+		`{"p":"bsv-20","op":"deploy","tick":"ordi","max":"100","dec":"not a num"}`: bsv20.ErrInvalidDecimals,
+	} {
+		_, err = bsv20.Decode([]byte(body))
+		require.ErrorIs(t, err, expected, body)
+	}
+}
+
+func TestNewTransfer(t *testing.T) {
+	owner, alice, bob := newLock(t), newLock(t), newLock(t)
+	txid := chainhash.DoubleHashH([]byte("deploy"))
+
+	// A BSV-21 token deployed at output 0, and part of it already received at output 1.
+	source := transaction.NewTransaction()
+	deployMint, err := bsv20.DeployMint("GOLD", 1000, 0, "")
+	require.NoError(t, err)
+	require.NoError(t, deployMint.Inscribe(source, owner))
+	id := bsv20.ID(&txid, 0)
+	received, err := bsv20.Transfer(id, 500)
+	require.NoError(t, err)
+	require.NoError(t, received.Inscribe(source, owner))
+
+	inputs := make([]*transaction.TransactionInput, len(source.Outputs))
+	for i, o := range source.Outputs {
+		inputs[i] = &transaction.TransactionInput{SourceTXID: &txid, SourceTxOutIndex: uint32(i)}
+		inputs[i].SetSourceTxOutput(o)
+	}
+
+	tx, err := bsv20.NewTransfer(inputs, []bsv20.Recipient{
+		{LockingScript: alice, Amount: 700},
+		{LockingScript: bob, Amount: 600},
+	}, owner)
+	require.NoError(t, err)
+	require.Len(t, tx.Outputs, 3)
+
+	for i, expected := range []struct {
+		lock   *script.Script
+		amount uint64
+	}{{alice, 700}, {bob, 600}, {owner, 200}} {
+		require.Equal(t, uint64(1), tx.Outputs[i].Satoshis)
+		inscription, lock, err := bsv20.Parse(tx.Outputs[i].LockingScript)
+		require.NoError(t, err)
+		require.Equal(t, bsv20.OpTransfer, inscription.Op)
+		require.Equal(t, id, inscription.ID)
+		require.Equal(t, expected.amount, inscription.Amount)
+		require.Equal(t, expected.lock.Bytes(), lock.Bytes())
+	}
+
+	_, err = bsv20.NewTransfer(inputs, []bsv20.Recipient{{LockingScript: alice, Amount: 1501}}, owner)
+	require.ErrorIs(t, err, bsv20.ErrInsufficientTokens)
+
+	other, err := bsv20.Mint("ordi", 10)
+	require.NoError(t, err)
+	otherTx := transaction.NewTransaction()
+	require.NoError(t, other.Inscribe(otherTx, owner))
+	otherInput := &transaction.TransactionInput{SourceTXID: &txid, SourceTxOutIndex: 2}
+	otherInput.SetSourceTxOutput(otherTx.Outputs[0])
+	_, err = bsv20.NewTransfer(append(inputs, otherInput), []bsv20.Recipient{{LockingScript: alice, Amount: 1}}, owner)
+	require.ErrorIs(t, err, bsv20.ErrTokenMismatch)
+
+	// Spending a token output holding more than 1 satoshi would shift the
+	// inscriptions of the following outputs.
+	heavy := &transaction.TransactionInput{SourceTXID: &txid, SourceTxOutIndex: 3}
+	heavy.SetSourceTxOutput(&transaction.TransactionOutput{Satoshis: 2, LockingScript: source.Outputs[1].LockingScript})
+	_, err = bsv20.NewTransfer(append(inputs, heavy), []bsv20.Recipient{{LockingScript: alice, Amount: 1}}, owner)
+	require.ErrorIs(t, err, bsv20.ErrNotOneSatoshi)
+}
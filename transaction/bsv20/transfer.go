@@ -0,0 +1,111 @@
+package bsv20
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// Recipient receives Amount tokens in an output locked with LockingScript.
+type Recipient struct {
+	LockingScript *script.Script
+	Amount        uint64
+}
+
+// Balance returns the token and amount held by the output spent by the
+// input: the amount of a mint or transfer inscription, or the supply of a
+// BSV-21 deploy+mint, whose id is the input's outpoint.
+func Balance(in *transaction.TransactionInput) (string, uint64, error) {
+	i, _, err := Parse(in.SourceTxScript())
+	if err != nil {
+		return "", 0, err
+	}
+	switch i.Op {
+	case OpMint, OpTransfer:
+		return i.Token(), i.Amount, nil
+	case OpDeployMint:
+		return ID(in.SourceTXID, in.SourceTxOutIndex), i.Amount, nil
+	}
+	return "", 0, ErrNotTransferable
+}
+
+// NewTransfer builds a transaction which spends the token inputs and sends
+// the tokens to the recipients, each in its own 1 satoshi transfer output.
+// Any tokens left over are returned to change in a last transfer output.
+// Every input must hold the same token in a 1 satoshi output, and have its
+// source output set.
+//
+// The token outputs come first and each input carries 1 satoshi, so the
+// inscriptions stay in order. Inputs and outputs to pay the fee should be
+// added after them.
+func NewTransfer(inputs []*transaction.TransactionInput, recipients []Recipient,
+	change *script.Script) (*transaction.Transaction, error) {
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	var token string
+	var available uint64
+	for idx, in := range inputs {
+		if sats := in.SourceTxSatoshis(); sats == nil {
+			return nil, transaction.ErrEmptyPreviousTx
+		} else if *sats != 1 {
+			return nil, ErrNotOneSatoshi
+		}
+		t, amount, err := Balance(in)
+		if err != nil {
+			return nil, err
+		}
+		if idx == 0 {
+			token = t
+		} else if !sameToken(token, t) {
+			return nil, ErrTokenMismatch
+		}
+		if available+amount < available {
+			return nil, ErrInvalidAmount
+		}
+		available += amount
+	}
+
+	var total uint64
+	for _, r := range recipients {
+		if r.Amount == 0 || total+r.Amount < total {
+			return nil, ErrInvalidAmount
+		}
+		total += r.Amount
+	}
+	if total > available {
+		return nil, ErrInsufficientTokens
+	}
+
+	tx := transaction.NewTransaction()
+	for _, in := range inputs {
+		tx.AddInput(in)
+	}
+	if available > total {
+		if change == nil {
+			return nil, transaction.ErrEmptyValues
+		}
+		recipients = append(slices.Clone(recipients), Recipient{LockingScript: change, Amount: available - total})
+	}
+	for _, r := range recipients {
+		i, err := Transfer(token, r.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if err = i.Inscribe(tx, r.LockingScript); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// sameToken compares tokens, where BSV-20 ticks are case insensitive.
+func sameToken(a, b string) bool {
+	if IsID(a) || IsID(b) {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
@@ -0,0 +1,126 @@
+package chaintracker
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// DefaultCacheSize is the number of results a Cache keeps when no size is given.
+const DefaultCacheSize = 1000
+
+type cacheKey struct {
+	root   chainhash.Hash
+	height uint32
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	expires time.Time
+}
+
+// Cache is a ChainTracker which remembers the merkle roots another
+// ChainTracker confirmed, keyed by merkle root and height. The least
+// recently used roots are evicted once size are held, and they expire after
+// ttl so that a reorg is eventually noticed. Invalid results and errors are
+// not cached, since the other tracker may simply not have seen the block
+// yet. It is safe for concurrent use.
+type Cache struct {
+	tracker ChainTracker
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	order   *list.List
+}
+
+// NewCache wraps tracker in a cache holding up to size roots, or
+// DefaultCacheSize if size is not positive. A ttl of zero keeps roots
+// until they are evicted.
+func NewCache(tracker ChainTracker, size int, ttl time.Duration) *Cache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &Cache{
+		tracker: tracker,
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[cacheKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *Cache) IsValidRootForHeight(root *chainhash.Hash, height uint32) (bool, error) {
+	return c.IsValidRootForHeightCtx(context.Background(), root, height)
+}
+
+func (c *Cache) IsValidRootForHeightCtx(ctx context.Context, root *chainhash.Hash, height uint32) (bool, error) {
+	key := cacheKey{root: *root, height: height}
+	if c.get(key) {
+		return true, nil
+	}
+	valid, err := WithContext(c.tracker).IsValidRootForHeightCtx(ctx, root, height)
+	if err != nil {
+		return false, err
+	}
+	if valid {
+		c.put(key)
+	}
+	return valid, nil
+}
+
+// get reports whether the root is cached as valid.
+func (c *Cache) get(key cacheKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	entry := e.Value.(*cacheEntry)
+	if c.ttl > 0 && !c.now().Before(entry.expires) {
+		c.order.Remove(e)
+		delete(c.entries, key)
+		return false
+	}
+	c.order.MoveToFront(e)
+	return true
+}
+
+func (c *Cache) put(key cacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &cacheEntry{key: key, expires: c.now().Add(c.ttl)}
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of roots held.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Clear removes every root, for example after a reorg.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[cacheKey]*list.Element)
+	c.order.Init()
+}
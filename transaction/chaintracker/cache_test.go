@@ -0,0 +1,118 @@
+package chaintracker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTracker answers with valid for the roots in roots, or err if set,
+// and counts its calls.
+type mockTracker struct {
+	mu    sync.Mutex
+	roots map[chainhash.Hash]bool
+	err   error
+	calls int
+}
+
+func (m *mockTracker) IsValidRootForHeight(root *chainhash.Hash, height uint32) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if m.err != nil {
+		return false, m.err
+	}
+	return m.roots[*root], nil
+}
+
+func (m *mockTracker) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+var (
+	rootA = chainhash.Hash{1}
+	rootB = chainhash.Hash{2}
+	rootC = chainhash.Hash{3}
+)
+
+func TestCache(t *testing.T) {
+	backend := &mockTracker{roots: map[chainhash.Hash]bool{rootA: true, rootB: true}}
+	cache := NewCache(backend, 2, 0)
+
+	valid, err := cache.IsValidRootForHeight(&rootA, 1)
+	require.NoError(t, err)
+	require.True(t, valid)
+	valid, err = cache.IsValidRootForHeight(&rootA, 1)
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Equal(t, 1, backend.Calls())
+
+	// The same root at another height is a different key.
+	_, err = cache.IsValidRootForHeight(&rootA, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, backend.Calls())
+
+	valid, err = cache.IsValidRootForHeight(&rootB, 1)
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Equal(t, 3, backend.Calls())
+	require.Equal(t, 2, cache.Len())
+
+	// rootA at height 1 was the least recently used and was evicted.
+	_, _ = cache.IsValidRootForHeight(&rootA, 2)
+	require.Equal(t, 3, backend.Calls())
+	_, _ = cache.IsValidRootForHeight(&rootA, 1)
+	require.Equal(t, 4, backend.Calls())
+
+	cache.Clear()
+	require.Equal(t, 0, cache.Len())
+	_, _ = cache.IsValidRootForHeight(&rootA, 1)
+	require.Equal(t, 5, backend.Calls())
+
+	// Invalid results are not cached, as the backend may not yet have
+	// the block.
+	valid, err = cache.IsValidRootForHeight(&rootC, 1)
+	require.NoError(t, err)
+	require.False(t, valid)
+	backend.mu.Lock()
+	backend.roots[rootC] = true
+	backend.mu.Unlock()
+	valid, err = cache.IsValidRootForHeight(&rootC, 1)
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Equal(t, 7, backend.Calls())
+
+	// Errors are not cached.
+	backend.err = errors.New("unavailable")
+	_, err = cache.IsValidRootForHeight(&rootA, 3)
+	require.ErrorIs(t, err, backend.err)
+	_, err = cache.IsValidRootForHeight(&rootA, 3)
+	require.ErrorIs(t, err, backend.err)
+	require.Equal(t, 9, backend.Calls())
+}
+
+func TestCacheTTL(t *testing.T) {
+	backend := &mockTracker{roots: map[chainhash.Hash]bool{rootA: true}}
+	cache := NewCache(backend, 0, time.Minute)
+	now := time.Unix(1700000000, 0)
+	cache.now = func() time.Time { return now }
+
+	_, _ = cache.IsValidRootForHeight(&rootA, 1)
+	now = now.Add(59 * time.Second)
+	_, _ = cache.IsValidRootForHeight(&rootA, 1)
+	require.Equal(t, 1, backend.Calls())
+
+	// After a reorg the cached result expires and is fetched again.
+	backend.roots[rootA] = false
+	now = now.Add(time.Second)
+	valid, err := cache.IsValidRootForHeight(&rootA, 1)
+	require.NoError(t, err)
+	require.False(t, valid)
+	require.Equal(t, 2, backend.Calls())
+}
@@ -1,7 +1,70 @@
 package chaintracker
 
-import "github.com/bsv-blockchain/go-sdk/chainhash"
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
 
 type ChainTracker interface {
 	IsValidRootForHeight(root *chainhash.Hash, height uint32) (bool, error)
 }
+
+// ChainTrackerCtx is a ChainTracker whose lookups can be cancelled or given
+// a deadline through a context.
+type ChainTrackerCtx interface {
+	IsValidRootForHeightCtx(ctx context.Context, root *chainhash.Hash, height uint32) (bool, error)
+}
+
+// WithContext returns tracker as a ChainTrackerCtx. Trackers which already
+// implement ChainTrackerCtx are returned as they are. Others are called in
+// a goroutine, so that a cancelled lookup returns the context's error at
+// once, though the underlying call runs to completion.
+func WithContext(tracker ChainTracker) ChainTrackerCtx {
+	if ct, ok := tracker.(ChainTrackerCtx); ok {
+		return ct
+	}
+	return contextAdapter{tracker}
+}
+
+type contextAdapter struct {
+	tracker ChainTracker
+}
+
+type lookupResult struct {
+	valid bool
+	err   error
+}
+
+func (a contextAdapter) IsValidRootForHeightCtx(ctx context.Context, root *chainhash.Hash, height uint32) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	result := make(chan lookupResult, 1)
+	go func() {
+		valid, err := a.tracker.IsValidRootForHeight(root, height)
+		result <- lookupResult{valid: valid, err: err}
+	}()
+	select {
+	case r := <-result:
+		return r.valid, r.err
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// BindContext returns a ChainTracker which calls tracker with ctx, for
+// passing a cancellable tracker to functions which take a ChainTracker,
+// such as MerklePath.Verify.
+func BindContext(ctx context.Context, tracker ChainTrackerCtx) ChainTracker {
+	return boundTracker{ctx: ctx, tracker: tracker}
+}
+
+type boundTracker struct {
+	ctx     context.Context
+	tracker ChainTrackerCtx
+}
+
+func (b boundTracker) IsValidRootForHeight(root *chainhash.Hash, height uint32) (bool, error) {
+	return b.tracker.IsValidRootForHeightCtx(b.ctx, root, height)
+}
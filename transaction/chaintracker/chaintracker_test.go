@@ -0,0 +1,65 @@
+package chaintracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+// slowTracker answers once release is closed.
+type slowTracker struct {
+	release chan struct{}
+}
+
+func (s *slowTracker) IsValidRootForHeight(root *chainhash.Hash, height uint32) (bool, error) {
+	<-s.release
+	return true, nil
+}
+
+func TestWithContext(t *testing.T) {
+	backend := &mockTracker{roots: map[chainhash.Hash]bool{rootA: true}}
+	tracker := WithContext(backend)
+	valid, err := tracker.IsValidRootForHeightCtx(context.Background(), &rootA, 1)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = tracker.IsValidRootForHeightCtx(ctx, &rootA, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, backend.Calls())
+
+	// A tracker which already takes a context is returned as it is.
+	cache := NewCache(backend, 0, 0)
+	require.Same(t, cache, WithContext(cache))
+
+	// A lookup in progress is abandoned when the context is done.
+	slow := &slowTracker{release: make(chan struct{})}
+	defer close(slow.release)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = WithContext(slow).IsValidRootForHeightCtx(ctx, &rootA, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBindContext(t *testing.T) {
+	backend := &mockTracker{roots: map[chainhash.Hash]bool{rootA: true}}
+	valid, err := BindContext(context.Background(), WithContext(backend)).IsValidRootForHeight(&rootA, 1)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = BindContext(ctx, NewCache(backend, 0, 0)).IsValidRootForHeight(&rootA, 1)
+	require.ErrorIs(t, err, context.Canceled)
+
+	slow := &slowTracker{release: make(chan struct{})}
+	defer close(slow.release)
+	q, err := NewQuorum(1, slow)
+	require.NoError(t, err)
+	_, err = BindContext(ctx, q).IsValidRootForHeight(&rootA, 1)
+	require.ErrorIs(t, err, context.Canceled)
+}
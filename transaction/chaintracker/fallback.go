@@ -0,0 +1,43 @@
+package chaintracker
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// Fallback is a ChainTracker which asks each ChainTracker in order and
+// returns the first answer, moving on to the next tracker only when one
+// returns an error.
+type Fallback []ChainTracker
+
+// NewFallback returns a Fallback trying the trackers in order.
+func NewFallback(trackers ...ChainTracker) Fallback {
+	return Fallback(trackers)
+}
+
+// IsValidRootForHeight returns the joined errors of every tracker if none
+// of them answers.
+func (f Fallback) IsValidRootForHeight(root *chainhash.Hash, height uint32) (bool, error) {
+	return f.IsValidRootForHeightCtx(context.Background(), root, height)
+}
+
+// IsValidRootForHeightCtx stops trying trackers once ctx is done.
+func (f Fallback) IsValidRootForHeightCtx(ctx context.Context, root *chainhash.Hash, height uint32) (bool, error) {
+	if len(f) == 0 {
+		return false, ErrNoTrackers
+	}
+	var errs []error
+	for _, tracker := range f {
+		valid, err := WithContext(tracker).IsValidRootForHeightCtx(ctx, root, height)
+		if err == nil {
+			return valid, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return false, errors.Join(errs...)
+}
@@ -0,0 +1,42 @@
+package chaintracker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallback(t *testing.T) {
+	down := &mockTracker{err: errors.New("unavailable")}
+	primary := &mockTracker{roots: map[chainhash.Hash]bool{rootA: true}}
+	secondary := &mockTracker{roots: map[chainhash.Hash]bool{rootA: true}}
+
+	_, err := NewFallback().IsValidRootForHeight(&rootA, 1)
+	require.ErrorIs(t, err, ErrNoTrackers)
+
+	f := NewFallback(down, primary, secondary)
+	valid, err := f.IsValidRootForHeight(&rootA, 1)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// An invalid root is an answer, so the next tracker is not asked.
+	valid, err = f.IsValidRootForHeight(&rootB, 1)
+	require.NoError(t, err)
+	require.False(t, valid)
+	require.Equal(t, 2, down.Calls())
+	require.Equal(t, 2, primary.Calls())
+	require.Equal(t, 0, secondary.Calls())
+
+	other := errors.New("timeout")
+	_, err = NewFallback(down, &mockTracker{err: other}).IsValidRootForHeight(&rootA, 1)
+	require.ErrorIs(t, err, down.err)
+	require.ErrorIs(t, err, other)
+
+	// The decorators compose.
+	var tracker ChainTracker = NewCache(NewFallback(down, primary), 0, 0)
+	valid, err = tracker.IsValidRootForHeight(&rootA, 5)
+	require.NoError(t, err)
+	require.True(t, valid)
+}
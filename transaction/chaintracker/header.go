@@ -0,0 +1,211 @@
+package chaintracker
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// HeaderSize is the size of a serialised block header.
+const HeaderSize = 80
+
+var (
+	ErrBadHeaderLength = errors.New("block header must be 80 bytes")
+	ErrBadBits         = errors.New("block header bits are invalid or easier than the proof of work limit")
+	ErrBadProofOfWork  = errors.New("block hash does not meet the target")
+	ErrHashMismatch    = errors.New("block header hash does not match its fields")
+	ErrMissingField    = errors.New("block header is missing a field")
+)
+
+// Header is a block header.
+type Header struct {
+	Version    uint32
+	PrevHash   chainhash.Hash
+	MerkleRoot chainhash.Hash
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+}
+
+// NewHeaderFromBytes parses a serialised 80 byte block header.
+func NewHeaderFromBytes(b []byte) (*Header, error) {
+	if len(b) != HeaderSize {
+		return nil, ErrBadHeaderLength
+	}
+	h := &Header{
+		Version:   binary.LittleEndian.Uint32(b[0:4]),
+		Timestamp: binary.LittleEndian.Uint32(b[68:72]),
+		Bits:      binary.LittleEndian.Uint32(b[72:76]),
+		Nonce:     binary.LittleEndian.Uint32(b[76:80]),
+	}
+	copy(h.PrevHash[:], b[4:36])
+	copy(h.MerkleRoot[:], b[36:68])
+	return h, nil
+}
+
+// NewHeaderFromHex parses a hex encoded block header.
+func NewHeaderFromHex(s string) (*Header, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewHeaderFromBytes(b)
+}
+
+// Bytes returns the 80 byte serialisation of the header.
+func (h *Header) Bytes() []byte {
+	b := make([]byte, HeaderSize)
+	binary.LittleEndian.PutUint32(b[0:4], h.Version)
+	copy(b[4:36], h.PrevHash[:])
+	copy(b[36:68], h.MerkleRoot[:])
+	binary.LittleEndian.PutUint32(b[68:72], h.Timestamp)
+	binary.LittleEndian.PutUint32(b[72:76], h.Bits)
+	binary.LittleEndian.PutUint32(b[76:80], h.Nonce)
+	return b
+}
+
+// Hex returns the hex encoded serialisation of the header.
+func (h *Header) Hex() string {
+	return hex.EncodeToString(h.Bytes())
+}
+
+// Hash returns the block hash, the double SHA256 of the serialised header.
+func (h *Header) Hash() chainhash.Hash {
+	return chainhash.DoubleHashH(h.Bytes())
+}
+
+// Target returns the target decoded from the header's bits.
+func (h *Header) Target() (*big.Int, error) {
+	target, ok := CompactToBig(h.Bits)
+	if !ok || target.Sign() <= 0 {
+		return nil, ErrBadBits
+	}
+	return target, nil
+}
+
+// CheckProofOfWork checks that the header's target is no easier than
+// powLimit and that its hash meets the target.
+func (h *Header) CheckProofOfWork(powLimit *big.Int) error {
+	target, err := h.Target()
+	if err != nil {
+		return err
+	}
+	if target.Cmp(powLimit) > 0 {
+		return ErrBadBits
+	}
+	hash := h.Hash()
+	if HashToBig(&hash).Cmp(target) > 0 {
+		return ErrBadProofOfWork
+	}
+	return nil
+}
+
+// Work returns the expected number of hashes needed to find a header
+// meeting the header's target, 2^256 / (target + 1), or zero if the bits
+// are invalid.
+func (h *Header) Work() *big.Int {
+	target, err := h.Target()
+	if err != nil {
+		return new(big.Int)
+	}
+	denominator := new(big.Int).Add(target, big.NewInt(1))
+	return new(big.Int).Div(new(big.Int).Lsh(big.NewInt(1), 256), denominator)
+}
+
+// BlockHeader returns the header as a BlockHeader at the given height.
+func (h *Header) BlockHeader(height uint32) *BlockHeader {
+	hash, prevHash, merkleRoot := h.Hash(), h.PrevHash, h.MerkleRoot
+	return &BlockHeader{
+		Hash:       &hash,
+		Height:     height,
+		Version:    h.Version,
+		MerkleRoot: &merkleRoot,
+		Time:       h.Timestamp,
+		Nonce:      h.Nonce,
+		Bits:       fmt.Sprintf("%08x", h.Bits),
+		PrevHash:   &prevHash,
+	}
+}
+
+// Header converts the BlockHeader to a Header. If the BlockHeader has a
+// hash it must match the hash of the converted header.
+func (b *BlockHeader) Header() (*Header, error) {
+	if b.MerkleRoot == nil {
+		return nil, ErrMissingField
+	}
+	bits, err := strconv.ParseUint(b.Bits, 16, 32)
+	if err != nil {
+		return nil, ErrBadBits
+	}
+	h := &Header{
+		Version:    b.Version,
+		MerkleRoot: *b.MerkleRoot,
+		Timestamp:  b.Time,
+		Bits:       uint32(bits),
+		Nonce:      b.Nonce,
+	}
+	// Only the genesis header has no previous block.
+	if b.PrevHash != nil {
+		h.PrevHash = *b.PrevHash
+	}
+	if b.Hash != nil {
+		if hash := h.Hash(); !hash.IsEqual(b.Hash) {
+			return nil, ErrHashMismatch
+		}
+	}
+	return h, nil
+}
+
+// CompactToBig decodes compact bits to a target. The mantissa is a signed
+// 24 bit number, so ok is false for negative and overflowing targets.
+func CompactToBig(bits uint32) (target *big.Int, ok bool) {
+	mantissa := bits & 0x007fffff
+	exponent := uint(bits >> 24)
+	negative := bits&0x00800000 != 0
+
+	if exponent <= 3 {
+		target = big.NewInt(int64(mantissa >> (8 * (3 - exponent))))
+	} else {
+		target = new(big.Int).Lsh(big.NewInt(int64(mantissa)), 8*(exponent-3))
+	}
+	if negative && mantissa != 0 {
+		return target, false
+	}
+	return target, target.BitLen() <= 256
+}
+
+// BigToCompact encodes a non-negative target as compact bits, losing any
+// precision beyond the 23 bit mantissa.
+func BigToCompact(target *big.Int) uint32 {
+	if target.Sign() == 0 {
+		return 0
+	}
+	exponent := uint((target.BitLen() + 7) / 8)
+	var mantissa uint32
+	if exponent <= 3 {
+		mantissa = uint32(target.Uint64() << (8 * (3 - exponent)))
+	} else {
+		mantissa = uint32(new(big.Int).Rsh(target, 8*(exponent-3)).Uint64())
+	}
+	// The top bit of the mantissa is the sign, so shift it into the exponent.
+	if mantissa&0x00800000 != 0 {
+		mantissa >>= 8
+		exponent++
+	}
+	return uint32(exponent<<24) | mantissa
+}
+
+// HashToBig interprets a block hash as a little endian number, for
+// comparison with a target.
+func HashToBig(h *chainhash.Hash) *big.Int {
+	b := make([]byte, chainhash.HashSize)
+	for i := range b {
+		b[i] = h[chainhash.HashSize-1-i]
+	}
+	return new(big.Int).SetBytes(b)
+}
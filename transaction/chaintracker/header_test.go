@@ -0,0 +1,86 @@
+package chaintracker
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const genesisHeaderHex = "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"
+
+func TestHeader(t *testing.T) {
+	h, err := NewHeaderFromHex(genesisHeaderHex)
+	require.NoError(t, err)
+	require.Equal(t, genesisHeaderHex, h.Hex())
+	require.Equal(t, uint32(1231006505), h.Timestamp)
+	require.Equal(t, uint32(0x1d00ffff), h.Bits)
+	require.Equal(t, uint32(2083236893), h.Nonce)
+	require.Equal(t, "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b", h.MerkleRoot.String())
+
+	hash := h.Hash()
+	require.Equal(t, "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f", hash.String())
+
+	target, err := h.Target()
+	require.NoError(t, err)
+	expected, _ := new(big.Int).SetString("00000000ffff0000000000000000000000000000000000000000000000000000", 16)
+	require.Equal(t, 0, expected.Cmp(target))
+	require.Equal(t, 0, big.NewInt(0x100010001).Cmp(h.Work()))
+
+	powLimit := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 224), big.NewInt(1))
+	require.NoError(t, h.CheckProofOfWork(powLimit))
+	require.ErrorIs(t, h.CheckProofOfWork(big.NewInt(1)), ErrBadBits)
+	h.Nonce++
+	require.ErrorIs(t, h.CheckProofOfWork(powLimit), ErrBadProofOfWork)
+
+	_, err = NewHeaderFromBytes(make([]byte, 79))
+	require.ErrorIs(t, err, ErrBadHeaderLength)
+}
+
+func TestCompact(t *testing.T) {
+	for _, bits := range []uint32{0x1d00ffff, 0x1b0404cb, 0x207fffff, 0x18009645, 0x03123456, 0x01120000} {
+		target, ok := CompactToBig(bits)
+		require.True(t, ok)
+		require.Equal(t, bits, BigToCompact(target), "%08x", bits)
+	}
+
+	// A negative mantissa and a target beyond 256 bits are invalid.
+	_, ok := CompactToBig(0x04923456)
+	require.False(t, ok)
+	_, ok = CompactToBig(0x2200ffff)
+	require.False(t, ok)
+	// A mantissa with the sign bit set is moved up a byte.
+	require.Equal(t, uint32(0x02008000), BigToCompact(big.NewInt(0x80)))
+}
+
+func TestHeaderBlockHeaderJSON(t *testing.T) {
+	h, err := NewHeaderFromHex(genesisHeaderHex)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(h.BlockHeader(0))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"hash": "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f",
+		"height": 0,
+		"version": 1,
+		"merkleroot": "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+		"time": 1231006505,
+		"nonce": 2083236893,
+		"bits": "1d00ffff",
+		"previousblockhash": "0000000000000000000000000000000000000000000000000000000000000000"
+	}`, string(b))
+
+	dto := &BlockHeader{}
+	require.NoError(t, json.Unmarshal(b, dto))
+	decoded, err := dto.Header()
+	require.NoError(t, err)
+	require.Equal(t, h, decoded)
+
+	dto.Nonce++
+	_, err = dto.Header()
+	require.ErrorIs(t, err, ErrHashMismatch)
+	dto.Bits = "not hex"
+	_, err = dto.Header()
+	require.ErrorIs(t, err, ErrBadBits)
+}
@@ -0,0 +1,190 @@
+package headers
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/transaction/chaintracker"
+)
+
+// daaWindow is the number of blocks the difficulty adjustment algorithm
+// measures the work and time of.
+const daaWindow = 144
+
+// nextBits returns the bits the network requires of a header extending
+// parent with the given timestamp, as computed by GetNextWorkRequired in the
+// node software. ok is false when they cannot be computed, either because
+// the params do not describe the difficulty adjustment or because the
+// ancestors needed are not stored.
+func (p *Params) nextBits(parent *node, timestamp uint32) (bits uint32, ok bool) {
+	if p.PowTargetSpacing <= 0 || p.PowLimit == nil {
+		return 0, false
+	}
+	if p.PowNoRetargeting {
+		return parent.header.Bits, true
+	}
+	if parent.height >= p.DAAHeight {
+		return p.nextDAABits(parent, timestamp)
+	}
+	return p.nextEDABits(parent, timestamp)
+}
+
+// nextDAABits follows the difficulty adjustment algorithm, which sets the
+// target from the work done over the last 144 blocks and the time taken.
+func (p *Params) nextDAABits(parent *node, timestamp uint32) (uint32, bool) {
+	limit := chaintracker.BigToCompact(p.PowLimit)
+	spacing := int64(p.PowTargetSpacing / time.Second)
+	if p.PowAllowMinDifficultyBlocks && int64(timestamp) > int64(parent.header.Timestamp)+2*spacing {
+		return limit, true
+	}
+	if parent.height < daaWindow {
+		return 0, false
+	}
+
+	last, first := suitable(parent), suitable(ancestor(parent, parent.height-daaWindow))
+	if last == nil || first == nil {
+		return 0, false
+	}
+
+	work := new(big.Int).Sub(last.chainWork, first.chainWork)
+	work.Mul(work, big.NewInt(spacing))
+	timespan := int64(last.header.Timestamp) - int64(first.header.Timestamp)
+	timespan = min(max(timespan, daaWindow/2*spacing), 2*daaWindow*spacing)
+	work.Div(work, big.NewInt(timespan))
+	if work.Sign() <= 0 {
+		return limit, true
+	}
+
+	// The target is (2^256 - work) / work.
+	target := new(big.Int).Lsh(big.NewInt(1), 256)
+	target.Sub(target, work).Div(target, work)
+	if target.Cmp(p.PowLimit) > 0 {
+		return limit, true
+	}
+	return chaintracker.BigToCompact(target), true
+}
+
+// nextEDABits follows the original difficulty adjustment, which retargets
+// every PowTargetTimespan, together with the emergency adjustment which
+// lowers the difficulty when six blocks take more than twelve hours.
+func (p *Params) nextEDABits(parent *node, timestamp uint32) (uint32, bool) {
+	limit := chaintracker.BigToCompact(p.PowLimit)
+	interval := uint32(p.PowTargetTimespan / p.PowTargetSpacing)
+	if interval == 0 {
+		return 0, false
+	}
+	height := parent.height + 1
+
+	if height%interval == 0 {
+		first := ancestor(parent, height-interval)
+		if first == nil {
+			return 0, false
+		}
+		return p.retarget(parent, first.header.Timestamp), true
+	}
+
+	if p.PowAllowMinDifficultyBlocks {
+		spacing := int64(p.PowTargetSpacing / time.Second)
+		if int64(timestamp) > int64(parent.header.Timestamp)+2*spacing {
+			return limit, true
+		}
+		// Use the bits of the last block not mined at the minimum difficulty.
+		n := parent
+		for n.height%interval != 0 && n.header.Bits == limit {
+			if n = n.parent; n == nil {
+				return 0, false
+			}
+		}
+		return n.header.Bits, true
+	}
+
+	bits := parent.header.Bits
+	if bits == limit {
+		return limit, true
+	}
+	if height < 7 {
+		return 0, false
+	}
+	mtp, ok := medianTimePast(parent)
+	if !ok {
+		return 0, false
+	}
+	mtp6, ok := medianTimePast(ancestor(parent, height-7))
+	if !ok {
+		return 0, false
+	}
+	if int64(mtp)-int64(mtp6) < 12*3600 {
+		return bits, true
+	}
+
+	// Lower the difficulty by 20%.
+	target, _ := chaintracker.CompactToBig(bits)
+	target.Add(target, new(big.Int).Rsh(target, 2))
+	if target.Cmp(p.PowLimit) > 0 {
+		return limit, true
+	}
+	return chaintracker.BigToCompact(target), true
+}
+
+// retarget scales the parent's target by the time the last interval took
+// relative to PowTargetTimespan, by at most a factor of four.
+func (p *Params) retarget(parent *node, firstTimestamp uint32) uint32 {
+	targetTimespan := int64(p.PowTargetTimespan / time.Second)
+	timespan := int64(parent.header.Timestamp) - int64(firstTimestamp)
+	timespan = min(max(timespan, targetTimespan/4), targetTimespan*4)
+
+	target, _ := chaintracker.CompactToBig(parent.header.Bits)
+	target.Mul(target, big.NewInt(timespan)).Div(target, big.NewInt(targetTimespan))
+	if target.Cmp(p.PowLimit) > 0 {
+		target = p.PowLimit
+	}
+	return chaintracker.BigToCompact(target)
+}
+
+// ancestor returns the ancestor of n at height, or nil if it is not stored.
+func ancestor(n *node, height uint32) *node {
+	for n != nil && n.height > height {
+		n = n.parent
+	}
+	if n == nil || n.height != height {
+		return nil
+	}
+	return n
+}
+
+// suitable returns whichever of n, its parent and grandparent has the
+// median timestamp, ordered as the node software does so that ties are
+// broken the same way. It returns nil if they are not all stored.
+func suitable(n *node) *node {
+	if n == nil || n.parent == nil || n.parent.parent == nil {
+		return nil
+	}
+	blocks := [3]*node{n.parent.parent, n.parent, n}
+	if blocks[0].header.Timestamp > blocks[2].header.Timestamp {
+		blocks[0], blocks[2] = blocks[2], blocks[0]
+	}
+	if blocks[0].header.Timestamp > blocks[1].header.Timestamp {
+		blocks[0], blocks[1] = blocks[1], blocks[0]
+	}
+	if blocks[1].header.Timestamp > blocks[2].header.Timestamp {
+		blocks[1], blocks[2] = blocks[2], blocks[1]
+	}
+	return blocks[1]
+}
+
+// medianTimePast returns the median timestamp of n and its ten ancestors.
+// ok is false if fewer are stored, unless the chain starts at the genesis
+// block, where the node software takes the median of those there are.
+func medianTimePast(n *node) (uint32, bool) {
+	if n == nil {
+		return 0, false
+	}
+	count, last := 0, n
+	for a := n; a != nil && count < medianTimeBlocks; a = a.parent {
+		count, last = count+1, a
+	}
+	if count < medianTimeBlocks && last.height != 0 {
+		return 0, false
+	}
+	return medianTime(n), true
+}
@@ -0,0 +1,387 @@
+// Package headers implements a ChainTracker backed by a local store of
+// block headers, so that merkle roots can be checked without trusting an
+// API provider.
+//
+// The store starts from a trusted checkpoint header and accepts headers
+// which extend any chain from it. Each header must link to a known parent,
+// meet the target in its bits, which in turn must be no easier than the
+// network's proof of work limit, and have a timestamp after the median of
+// the previous eleven. The chain with the most accumulated work is the main
+// chain, and the store switches to another chain as soon as it has more
+// work. Merkle roots are only checked against the main chain.
+//
+// The bits of each header must also be those the network's difficulty
+// adjustment requires at its height, given its ancestors. They can only be
+// computed once enough ancestors are stored though: the difficulty
+// adjustment algorithm needs the previous 146 headers, and the original
+// adjustment the previous 2016 at a retarget. Headers closer than that to
+// the checkpoint are only checked against the proof of work limit.
+//
+// Headers are appended to a file as they are accepted, 80 bytes each, and
+// replayed when the store is opened.
+package headers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/transaction/chaintracker"
+)
+
+// HeaderSize is the size of a serialised block header.
+const HeaderSize = chaintracker.HeaderSize
+
+// MaxFutureBlockTime is how far ahead of the local clock a header's timestamp may be.
+const MaxFutureBlockTime = 2 * time.Hour
+
+// medianTimeBlocks is the number of previous headers whose median timestamp
+// a header's timestamp must exceed.
+const medianTimeBlocks = 11
+
+var (
+	ErrBadHeaderLength  = chaintracker.ErrBadHeaderLength
+	ErrNoCheckpoint     = errors.New("checkpoint header not supplied")
+	ErrUnknownParent    = errors.New("block header does not extend a known header")
+	ErrBadBits          = chaintracker.ErrBadBits
+	ErrUnexpectedBits   = errors.New("block bits do not match the difficulty the network requires")
+	ErrBadProofOfWork   = chaintracker.ErrBadProofOfWork
+	ErrTimeTooOld       = errors.New("block timestamp is not after the median time of the previous blocks")
+	ErrTimeTooNew       = errors.New("block timestamp is too far in the future")
+	ErrUnknownHeight    = errors.New("no header at height in the main chain")
+	ErrHeightOverflow   = errors.New("block height overflows")
+	ErrStoreClosed      = errors.New("header store is closed")
+	ErrCorruptStoreFile = errors.New("header store file contains an invalid header")
+)
+
+// Params are the consensus parameters of a network.
+type Params struct {
+	// PowLimit is the easiest target a header may have.
+	PowLimit *big.Int
+	// PowTargetSpacing is the intended time between blocks. If it is zero,
+	// the bits of headers are only checked against PowLimit.
+	PowTargetSpacing time.Duration
+	// PowTargetTimespan is how often the original difficulty adjustment
+	// retargets, every PowTargetTimespan / PowTargetSpacing blocks.
+	PowTargetTimespan time.Duration
+	// DAAHeight is the height after which the difficulty adjustment
+	// algorithm sets the bits of every block from the previous 144. Up to
+	// it, the original adjustment applies, along with the emergency
+	// adjustment which lowers the difficulty when blocks are slow.
+	DAAHeight uint32
+	// PowAllowMinDifficultyBlocks allows a block at PowLimit when it is more
+	// than twice PowTargetSpacing after its parent.
+	PowAllowMinDifficultyBlocks bool
+	// PowNoRetargeting keeps the difficulty of the checkpoint for every block.
+	PowNoRetargeting bool
+}
+
+var (
+	// MainNet are the parameters of the main network.
+	MainNet = Params{
+		PowLimit:          new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 224), big.NewInt(1)),
+		PowTargetSpacing:  10 * time.Minute,
+		PowTargetTimespan: 14 * 24 * time.Hour,
+		DAAHeight:         504031,
+	}
+	// TestNet are the parameters of the test network.
+	TestNet = Params{
+		PowLimit:                    new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 224), big.NewInt(1)),
+		PowTargetSpacing:            10 * time.Minute,
+		PowTargetTimespan:           14 * 24 * time.Hour,
+		DAAHeight:                   1188697,
+		PowAllowMinDifficultyBlocks: true,
+	}
+	// RegTest are the parameters of regression test networks.
+	RegTest = Params{
+		PowLimit:                    new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)),
+		PowTargetSpacing:            10 * time.Minute,
+		PowTargetTimespan:           14 * 24 * time.Hour,
+		PowAllowMinDifficultyBlocks: true,
+		PowNoRetargeting:            true,
+	}
+)
+
+// Checkpoint is the trusted header the store starts from.
+type Checkpoint struct {
+	Header []byte
+	Height uint32
+	// ChainWork is the accumulated work of the chain up to and including the
+	// checkpoint. If nil only the checkpoint's own work is counted, which is
+	// enough to compare chains descending from it.
+	ChainWork *big.Int
+}
+
+type node struct {
+	header    *chaintracker.Header
+	hash      chainhash.Hash
+	height    uint32
+	chainWork *big.Int
+	parent    *node
+}
+
+// Store is a block header store. It is safe for concurrent use.
+type Store struct {
+	mu     sync.RWMutex
+	params Params
+	file   *os.File
+	nodes  map[chainhash.Hash]*node
+	// main is the main chain, indexed by height less the checkpoint height.
+	main   []*node
+	closed bool
+	now    func() time.Time
+}
+
+// Open opens the header store at path, creating it if needed, and replays
+// the headers already in it. An empty path opens a store which is only
+// kept in memory.
+func Open(path string, params Params, checkpoint Checkpoint) (*Store, error) {
+	if checkpoint.Header == nil {
+		return nil, ErrNoCheckpoint
+	}
+	h, err := chaintracker.NewHeaderFromBytes(checkpoint.Header)
+	if err != nil {
+		return nil, err
+	}
+	root := &node{header: h, hash: h.Hash(), height: checkpoint.Height, chainWork: checkpoint.ChainWork}
+	if root.chainWork == nil {
+		root.chainWork = h.Work()
+	}
+
+	s := &Store{
+		params: params,
+		nodes:  map[chainhash.Hash]*node{root.hash: root},
+		main:   []*node{root},
+		now:    time.Now,
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	if s.file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600); err != nil {
+		return nil, err
+	}
+	if err = s.replay(); err != nil {
+		_ = s.file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay loads the headers in the store file, dropping any partial header
+// left by an interrupted write.
+func (s *Store) replay() error {
+	b, err := io.ReadAll(s.file)
+	if err != nil {
+		return err
+	}
+	complete := len(b) - len(b)%HeaderSize
+	for offset := 0; offset < complete; offset += HeaderSize {
+		if _, err = s.add(b[offset:offset+HeaderSize], false); err != nil {
+			return fmt.Errorf("%w at offset %d: %w", ErrCorruptStoreFile, offset, err)
+		}
+	}
+	if complete != len(b) {
+		if err = s.file.Truncate(int64(complete)); err != nil {
+			return err
+		}
+	}
+	_, err = s.file.Seek(int64(complete), io.SeekStart)
+	return err
+}
+
+// Close closes the store file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
+
+// Add validates and stores a serialised header. Headers which are already
+// stored are ignored. It reports whether the main chain changed, either
+// because the header extends it or because the header's chain now has more
+// work, in which case the store reorganises onto it.
+func (s *Store) Add(header []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false, ErrStoreClosed
+	}
+	return s.add(header, true)
+}
+
+// AddHeaders adds concatenated serialised headers in order, stopping at
+// the first invalid header. It reports whether the main chain changed.
+func (s *Store) AddHeaders(headers []byte) (bool, error) {
+	if len(headers)%HeaderSize != 0 {
+		return false, ErrBadHeaderLength
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false, ErrStoreClosed
+	}
+	changed := false
+	for offset := 0; offset < len(headers); offset += HeaderSize {
+		c, err := s.add(headers[offset:offset+HeaderSize], true)
+		changed = changed || c
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+func (s *Store) add(b []byte, persist bool) (bool, error) {
+	h, err := chaintracker.NewHeaderFromBytes(b)
+	if err != nil {
+		return false, err
+	}
+	hash := h.Hash()
+	if _, ok := s.nodes[hash]; ok {
+		return false, nil
+	}
+	parent, ok := s.nodes[h.PrevHash]
+	if !ok {
+		return false, ErrUnknownParent
+	}
+	if parent.height == ^uint32(0) {
+		return false, ErrHeightOverflow
+	}
+
+	if err = h.CheckProofOfWork(s.params.PowLimit); err != nil {
+		return false, err
+	}
+	if bits, ok := s.params.nextBits(parent, h.Timestamp); ok && h.Bits != bits {
+		return false, ErrUnexpectedBits
+	}
+	if h.Timestamp <= medianTime(parent) {
+		return false, ErrTimeTooOld
+	}
+	if persist && time.Unix(int64(h.Timestamp), 0).After(s.now().Add(MaxFutureBlockTime)) {
+		return false, ErrTimeTooNew
+	}
+
+	if persist && s.file != nil {
+		if _, err = s.file.Write(b); err != nil {
+			return false, err
+		}
+	}
+
+	n := &node{
+		header:    h,
+		hash:      hash,
+		height:    parent.height + 1,
+		chainWork: new(big.Int).Add(parent.chainWork, h.Work()),
+		parent:    parent,
+	}
+	s.nodes[hash] = n
+
+	if n.chainWork.Cmp(s.tip().chainWork) <= 0 {
+		return false, nil
+	}
+	s.reorganise(n)
+	return true, nil
+}
+
+// reorganise makes the chain ending at tip the main chain.
+func (s *Store) reorganise(tip *node) {
+	var branch []*node
+	n := tip
+	for ; !s.inMain(n); n = n.parent {
+		branch = append(branch, n)
+	}
+	slices.Reverse(branch)
+	s.main = append(s.main[:n.height-s.main[0].height+1], branch...)
+}
+
+func (s *Store) inMain(n *node) bool {
+	i := int(n.height) - int(s.main[0].height)
+	return i >= 0 && i < len(s.main) && s.main[i] == n
+}
+
+func (s *Store) tip() *node {
+	return s.main[len(s.main)-1]
+}
+
+// medianTime returns the median timestamp of n and up to ten of its ancestors.
+func medianTime(n *node) uint32 {
+	times := make([]uint32, 0, medianTimeBlocks)
+	for ; n != nil && len(times) < medianTimeBlocks; n = n.parent {
+		times = append(times, n.header.Timestamp)
+	}
+	slices.Sort(times)
+	return times[len(times)/2]
+}
+
+// Tip returns the height and hash of the last header of the main chain.
+func (s *Store) Tip() (uint32, chainhash.Hash) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tip := s.tip()
+	return tip.height, tip.hash
+}
+
+// ChainWork returns the accumulated work of the main chain.
+func (s *Store) ChainWork() *big.Int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return new(big.Int).Set(s.tip().chainWork)
+}
+
+// Header returns the serialised header at height in the main chain.
+func (s *Store) Header(height uint32) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n, err := s.at(height)
+	if err != nil {
+		return nil, err
+	}
+	return n.header.Bytes(), nil
+}
+
+// Hash returns the hash of the header at height in the main chain.
+func (s *Store) Hash(height uint32) (*chainhash.Hash, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n, err := s.at(height)
+	if err != nil {
+		return nil, err
+	}
+	hash := n.hash
+	return &hash, nil
+}
+
+func (s *Store) at(height uint32) (*node, error) {
+	base := s.main[0].height
+	if height < base || int(height-base) >= len(s.main) {
+		return nil, ErrUnknownHeight
+	}
+	return s.main[height-base], nil
+}
+
+// IsValidRootForHeight reports whether root is the merkle root of the
+// header at height in the main chain. It returns ErrUnknownHeight for
+// heights outside the stored chain.
+func (s *Store) IsValidRootForHeight(root *chainhash.Hash, height uint32) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n, err := s.at(height)
+	if err != nil {
+		return false, err
+	}
+	return n.header.MerkleRoot.IsEqual(root), nil
+}
@@ -0,0 +1,208 @@
+package headers_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/transaction/chaintracker"
+	"github.com/bsv-blockchain/go-sdk/transaction/chaintracker/headers"
+	"github.com/stretchr/testify/require"
+)
+
+var _ chaintracker.ChainTracker = (*headers.Store)(nil)
+
+const (
+	genesisHex = "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"
+	block1Hex  = "010000006fe28c0ab6f1b372c1a6a246ae63f74f931e8365e15a089c68d6190000000000982051fd1e4ba744bbbe680e1fee14677ba1a3c3540bf7b1cdb606e857233e0e61bc6649ffff001d01e36299"
+	block2Hex  = "010000004860eb18bf1b1620e37e9490fc8a427514416fd75159ab86688e9a8300000000d5fdcc541e25de1c7a5addedf24858b8bb665c9f36ef744ee42c316022c90f9bb0bc6649ffff001d08d2bd61"
+)
+
+const regTestBits = 0x207fffff
+
+// mine returns a header extending prev which meets the regtest target.
+func mine(t *testing.T, prev []byte, merkleRoot string, timestamp uint32) []byte {
+	return mineBits(t, prev, merkleRoot, timestamp, regTestBits)
+}
+
+// mineBits returns a header extending prev which meets the target in bits.
+func mineBits(t *testing.T, prev []byte, merkleRoot string, timestamp uint32, bits uint32) []byte {
+	target, ok := chaintracker.CompactToBig(bits)
+	require.True(t, ok)
+	b := make([]byte, headers.HeaderSize)
+	binary.LittleEndian.PutUint32(b[0:4], 1)
+	prevHash := chainhash.DoubleHashH(prev)
+	copy(b[4:36], prevHash[:])
+	root := chainhash.DoubleHashH([]byte(merkleRoot))
+	copy(b[36:68], root[:])
+	binary.LittleEndian.PutUint32(b[68:72], timestamp)
+	binary.LittleEndian.PutUint32(b[72:76], bits)
+	for nonce := uint32(0); ; nonce++ {
+		binary.LittleEndian.PutUint32(b[76:80], nonce)
+		if hash := chainhash.DoubleHashH(b); chaintracker.HashToBig(&hash).Cmp(target) <= 0 {
+			return b
+		}
+	}
+}
+
+func regTestGenesis() []byte {
+	b := make([]byte, headers.HeaderSize)
+	binary.LittleEndian.PutUint32(b[68:72], 1600000000)
+	binary.LittleEndian.PutUint32(b[72:76], regTestBits)
+	return b
+}
+
+func TestMainNetHeaders(t *testing.T) {
+	genesis, _ := hex.DecodeString(genesisHex)
+	block1, _ := hex.DecodeString(block1Hex)
+	block2, _ := hex.DecodeString(block2Hex)
+
+	s, err := headers.Open("", headers.MainNet, headers.Checkpoint{Header: genesis})
+	require.NoError(t, err)
+	changed, err := s.AddHeaders(append(block1, block2...))
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	height, hash := s.Tip()
+	require.Equal(t, uint32(2), height)
+	require.Equal(t, "000000006a625f06636b8bb6ac7b960a8d03705d1ace08b1a19da3fdcc99ddbd", hash.String())
+
+	root, err := chainhash.NewHashFromHex("0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098")
+	require.NoError(t, err)
+	ok, err := s.IsValidRootForHeight(root, 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = s.IsValidRootForHeight(root, 2)
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, err = s.IsValidRootForHeight(root, 3)
+	require.ErrorIs(t, err, headers.ErrUnknownHeight)
+
+	// Three headers of difficulty 1 each.
+	require.Equal(t, 0, s.ChainWork().Cmp(big.NewInt(3*0x100010001)))
+
+	// Tampering with the nonce breaks the proof of work.
+	bad, _ := hex.DecodeString(block2Hex)
+	bad[79] ^= 0xff
+	_, err = s.Add(bad)
+	require.ErrorIs(t, err, headers.ErrBadProofOfWork)
+}
+
+func TestDifficultyAdjustment(t *testing.T) {
+	// A network on the difficulty adjustment algorithm from the start, with
+	// a target which is quick to mine.
+	params := headers.Params{
+		PowLimit:          headers.RegTest.PowLimit,
+		PowTargetSpacing:  10 * time.Minute,
+		PowTargetTimespan: 14 * 24 * time.Hour,
+	}
+	const bits = 0x2000ffff
+	genesis := regTestGenesis()
+	binary.LittleEndian.PutUint32(genesis[72:76], bits)
+	s, err := headers.Open("", params, headers.Checkpoint{Header: genesis, Height: 1000})
+	require.NoError(t, err)
+
+	// The bits of the first 146 headers cannot be computed from the store,
+	// as the algorithm looks back further than the checkpoint.
+	addChain := func(spacing uint32) []byte {
+		prev := genesis
+		for i := uint32(1); i <= 146; i++ {
+			prev = mineBits(t, prev, "main", 1600000000+i*spacing, bits)
+			_, err := s.Add(prev)
+			require.NoError(t, err)
+		}
+		return prev
+	}
+
+	// A block every ten minutes keeps the difficulty, so a header must have
+	// the same bits, not easier or harder ones.
+	tip := addChain(600)
+	_, err = s.Add(mineBits(t, tip, "easier", 1600000000+147*600, 0x2001ffff))
+	require.ErrorIs(t, err, headers.ErrUnexpectedBits)
+	_, err = s.Add(mineBits(t, tip, "harder", 1600000000+147*600, 0x1f7fffff))
+	require.ErrorIs(t, err, headers.ErrUnexpectedBits)
+	changed, err := s.Add(mineBits(t, tip, "main", 1600000000+147*600, bits))
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	// A chain of blocks every five minutes raises the difficulty, so its
+	// next header cannot keep the easier bits.
+	tip = addChain(300)
+	_, err = s.Add(mineBits(t, tip, "easier", 1600000000+147*300, bits))
+	require.ErrorIs(t, err, headers.ErrUnexpectedBits)
+}
+
+func TestReorgAndPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "headers.dat")
+	genesis := regTestGenesis()
+	checkpoint := headers.Checkpoint{Header: genesis, Height: 100}
+
+	s, err := headers.Open(path, headers.RegTest, checkpoint)
+	require.NoError(t, err)
+
+	// The main chain: 101..105
+	mainChain := [][]byte{genesis}
+	for i := 1; i <= 5; i++ {
+		h := mine(t, mainChain[i-1], "main", 1600000000+uint32(i)*600)
+		changed, err := s.Add(h)
+		require.NoError(t, err)
+		require.True(t, changed)
+		mainChain = append(mainChain, h)
+	}
+	root := chainhash.DoubleHashH([]byte("main"))
+	ok, err := s.IsValidRootForHeight(&root, 104)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// A fork from 102 which overtakes the main chain at its fourth header.
+	fork := [][]byte{mainChain[2]}
+	for i := 1; i <= 4; i++ {
+		h := mine(t, fork[i-1], "fork", 1600000000+uint32(i)*600+1200+1)
+		changed, err := s.Add(h)
+		require.NoError(t, err)
+		require.Equal(t, i == 4, changed)
+		fork = append(fork, h)
+	}
+	height, hash := s.Tip()
+	require.Equal(t, uint32(106), height)
+	require.Equal(t, chainhash.DoubleHashH(fork[4]), hash)
+	forkRoot := chainhash.DoubleHashH([]byte("fork"))
+	ok, err = s.IsValidRootForHeight(&forkRoot, 104)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = s.IsValidRootForHeight(&root, 102)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Headers which do not connect or go back in time are rejected.
+	_, err = s.Add(mine(t, make([]byte, headers.HeaderSize), "orphan", 1600009000))
+	require.ErrorIs(t, err, headers.ErrUnknownParent)
+	_, err = s.Add(mine(t, fork[4], "old", 1600000000))
+	require.ErrorIs(t, err, headers.ErrTimeTooOld)
+	require.NoError(t, s.Close())
+
+	// A partial write is dropped and the chain is rebuilt from disk.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{1, 2, 3})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s, err = headers.Open(path, headers.RegTest, checkpoint)
+	require.NoError(t, err)
+	defer s.Close()
+	height, hash = s.Tip()
+	require.Equal(t, uint32(106), height)
+	require.Equal(t, chainhash.DoubleHashH(fork[4]), hash)
+	stored, err := s.Header(103)
+	require.NoError(t, err)
+	require.Equal(t, fork[1], stored)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, int64(9*headers.HeaderSize), info.Size())
+}
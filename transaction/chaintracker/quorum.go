@@ -0,0 +1,104 @@
+package chaintracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+var (
+	ErrNoTrackers   = errors.New("no chain trackers supplied")
+	ErrBadThreshold = errors.New("quorum threshold must be between 1 and the number of chain trackers")
+	ErrNoQuorum     = errors.New("chain trackers did not reach a quorum")
+)
+
+// Quorum is a ChainTracker which asks several ChainTrackers concurrently and
+// returns an answer once Threshold of them agree on it. Trackers which
+// return an error count towards neither answer.
+type Quorum struct {
+	Trackers  []ChainTracker
+	Threshold int
+}
+
+// NewQuorum returns a Quorum which requires threshold of the trackers to agree.
+func NewQuorum(threshold int, trackers ...ChainTracker) (*Quorum, error) {
+	if len(trackers) == 0 {
+		return nil, ErrNoTrackers
+	}
+	if threshold < 1 || threshold > len(trackers) {
+		return nil, ErrBadThreshold
+	}
+	return &Quorum{Trackers: trackers, Threshold: threshold}, nil
+}
+
+type quorumResult struct {
+	valid bool
+	err   error
+}
+
+// IsValidRootForHeight returns as soon as Threshold trackers agree, without
+// waiting for the rest. It returns an error wrapping ErrNoQuorum and the
+// trackers' errors if neither answer can reach the threshold.
+func (q *Quorum) IsValidRootForHeight(root *chainhash.Hash, height uint32) (bool, error) {
+	return q.IsValidRootForHeightCtx(context.Background(), root, height)
+}
+
+// IsValidRootForHeightCtx cancels the lookups still running once the
+// quorum is reached or ctx is done.
+func (q *Quorum) IsValidRootForHeightCtx(ctx context.Context, root *chainhash.Hash, height uint32) (bool, error) {
+	if len(q.Trackers) == 0 {
+		return false, ErrNoTrackers
+	}
+	if q.Threshold < 1 || q.Threshold > len(q.Trackers) {
+		return false, ErrBadThreshold
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The channel is buffered so that trackers still running when the
+	// quorum is reached do not block.
+	results := make(chan quorumResult, len(q.Trackers))
+	for _, tracker := range q.Trackers {
+		go func(tracker ChainTracker) {
+			valid, err := WithContext(tracker).IsValidRootForHeightCtx(ctx, root, height)
+			results <- quorumResult{valid: valid, err: err}
+		}(tracker)
+	}
+
+	var valid, invalid int
+	var errs []error
+	for remaining := len(q.Trackers); remaining > 0; remaining-- {
+		var r quorumResult
+		select {
+		case r = <-results:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+		switch {
+		case r.err != nil:
+			errs = append(errs, r.err)
+		case r.valid:
+			valid++
+		default:
+			invalid++
+		}
+		if valid >= q.Threshold {
+			return true, nil
+		}
+		if invalid >= q.Threshold {
+			return false, nil
+		}
+		// Stop early once neither answer can reach the threshold.
+		if valid+remaining-1 < q.Threshold && invalid+remaining-1 < q.Threshold {
+			break
+		}
+	}
+	err := fmt.Errorf("%w: %d valid, %d invalid of %d needed", ErrNoQuorum, valid, invalid, q.Threshold)
+	if len(errs) > 0 {
+		err = fmt.Errorf("%w: %w", err, errors.Join(errs...))
+	}
+	return false, err
+}
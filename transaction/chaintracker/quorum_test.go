@@ -0,0 +1,49 @@
+package chaintracker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuorum(t *testing.T) {
+	honest := func() *mockTracker { return &mockTracker{roots: map[chainhash.Hash]bool{rootA: true}} }
+	lying := &mockTracker{roots: map[chainhash.Hash]bool{rootB: true}}
+	down := &mockTracker{err: errors.New("unavailable")}
+
+	_, err := NewQuorum(1)
+	require.ErrorIs(t, err, ErrNoTrackers)
+	_, err = NewQuorum(3, honest(), honest())
+	require.ErrorIs(t, err, ErrBadThreshold)
+	_, err = NewQuorum(0, honest())
+	require.ErrorIs(t, err, ErrBadThreshold)
+
+	q, err := NewQuorum(2, honest(), lying, honest())
+	require.NoError(t, err)
+	valid, err := q.IsValidRootForHeight(&rootA, 1)
+	require.NoError(t, err)
+	require.True(t, valid)
+	valid, err = q.IsValidRootForHeight(&rootB, 1)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// A tracker which is down counts towards neither answer.
+	q, err = NewQuorum(2, honest(), down, honest())
+	require.NoError(t, err)
+	valid, err = q.IsValidRootForHeight(&rootA, 1)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	q, err = NewQuorum(2, honest(), down, lying)
+	require.NoError(t, err)
+	_, err = q.IsValidRootForHeight(&rootA, 1)
+	require.ErrorIs(t, err, ErrNoQuorum)
+	require.ErrorIs(t, err, down.err)
+
+	q, err = NewQuorum(2, honest(), lying)
+	require.NoError(t, err)
+	_, err = q.IsValidRootForHeight(&rootA, 1)
+	require.ErrorIs(t, err, ErrNoQuorum)
+}
@@ -43,17 +43,26 @@ func NewWhatsOnChain(network Network, apiKey string) *WhatsOnChain {
 	}
 }
 
-// Assuming BlockHeader is defined elsewhere
-func (w *WhatsOnChain) GetBlockHeader(height uint32) (header *BlockHeader, err error) {
+// GetBlockHeader returns the header at height, or nil if there is none.
+func (w *WhatsOnChain) GetBlockHeader(height uint32) (*BlockHeader, error) {
+	return w.GetBlockHeaderCtx(context.Background(), height)
+}
+
+// GetBlockHeaderCtx is GetBlockHeader with a context for the request.
+func (w *WhatsOnChain) GetBlockHeaderCtx(ctx context.Context, height uint32) (header *BlockHeader, err error) {
 	url := fmt.Sprintf("%s/block/%d/header", w.baseURL, height)
-	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Authorization", w.ApiKey)
 
-	resp, err := http.DefaultClient.Do(req)
+	client := w.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -81,8 +90,15 @@ func (w *WhatsOnChain) GetBlockHeader(height uint32) (header *BlockHeader, err e
 }
 
 func (w *WhatsOnChain) IsValidRootForHeight(root *chainhash.Hash, height uint32) (bool, error) {
-	if header, err := w.GetBlockHeader(height); err != nil {
+	return w.IsValidRootForHeightCtx(context.Background(), root, height)
+}
+
+// IsValidRootForHeightCtx reports false when there is no header at height.
+func (w *WhatsOnChain) IsValidRootForHeightCtx(ctx context.Context, root *chainhash.Hash, height uint32) (bool, error) {
+	if header, err := w.GetBlockHeaderCtx(ctx, height); err != nil {
 		return false, err
+	} else if header == nil {
+		return false, nil
 	} else {
 		return header.MerkleRoot.IsEqual(root), nil
 	}
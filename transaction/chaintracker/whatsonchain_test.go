@@ -3,6 +3,7 @@
 package chaintracker
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -176,3 +177,47 @@ func TestWhatsOnChainIsValidRootForHeightInvalidRoot(t *testing.T) {
 		t.Fatalf("expected isValid to be false, got true")
 	}
 }
+
+func TestWhatsOnChainIsValidRootForHeightNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	woc := &WhatsOnChain{
+		Network: "main",
+		baseURL: ts.URL,
+		client:  ts.Client(),
+	}
+
+	root := chainhash.HashH([]byte("test merkle root"))
+	isValid, err := woc.IsValidRootForHeight(&root, 100000000)
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestWhatsOnChainIsValidRootForHeightCtx(t *testing.T) {
+	merkleRootHash := chainhash.HashH([]byte("test merkle root"))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(&BlockHeader{MerkleRoot: &merkleRootHash})
+	}))
+	defer ts.Close()
+
+	woc := &WhatsOnChain{
+		Network: "main",
+		baseURL: ts.URL,
+		client:  ts.Client(),
+	}
+
+	isValid, err := woc.IsValidRootForHeightCtx(context.Background(), &merkleRootHash, 100)
+	require.NoError(t, err)
+	require.True(t, isValid)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = woc.IsValidRootForHeightCtx(ctx, &merkleRootHash, 100)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = woc.GetBlockHeaderCtx(ctx, 100)
+	require.ErrorIs(t, err, context.Canceled)
+}
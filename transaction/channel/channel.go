@@ -0,0 +1,346 @@
+// Package channel implements a unidirectional payment channel built on
+// nSequence replacement.
+//
+// The payer locks the channel capacity in a 2-of-2 multisig funding output
+// shared with the payee. Each payment is a new commitment transaction which
+// spends the funding output, pays the payee the running total and refunds
+// the rest to the payer. Commitments carry a future lock time, so they stay
+// non-final and each may be replaced by one with a higher input sequence
+// number. The payer signs every commitment and the payee verifies it against
+// the previous state. Either party can close the channel by producing a
+// final transaction with MaxTxInSequenceNum, which the other co-signs.
+package channel
+
+import (
+	"errors"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/multisig"
+)
+
+var (
+	ErrNoLockTime          = errors.New("commitment lock time must be set for commitments to be replaceable")
+	ErrMissingParams       = errors.New("channel parameters are incomplete")
+	ErrNotParticipant      = errors.New("key is neither the payer nor the payee of the channel")
+	ErrNotPayer            = errors.New("only the payer can make payments")
+	ErrNotPayee            = errors.New("only the payee can receive payments")
+	ErrInsufficientFunds   = errors.New("payment exceeds the channel capacity")
+	ErrSequenceExhausted   = errors.New("no sequence numbers left for commitments")
+	ErrStaleSequence       = errors.New("update sequence is not greater than the current state")
+	ErrDecreasingPayment   = errors.New("update pays the payee less than the current state")
+	ErrPaymentMismatch     = errors.New("closing update does not match the current state")
+	ErrInvalidSignature    = errors.New("update signature is invalid")
+	ErrNoUpdate            = errors.New("no update has been received")
+	ErrNotFinal            = errors.New("update is not a closing update")
+	ErrUnexpectedFinal     = errors.New("closing update received as a payment")
+	ErrChannelClosed       = errors.New("channel is closed")
+	ErrZeroPayment         = errors.New("payment amount must be greater than zero")
+	ErrPayeeOutputTooSmall = errors.New("payee output has no value")
+)
+
+// Params are the terms of the channel agreed by both parties.
+type Params struct {
+	// FundingTxID and FundingVout identify the funding output.
+	FundingTxID *chainhash.Hash
+	FundingVout uint32
+	// Capacity is the value of the funding output in satoshis.
+	Capacity uint64
+	// PayerKey and PayeeKey are the keys of the 2-of-2 funding output.
+	PayerKey *ec.PublicKey
+	PayeeKey *ec.PublicKey
+	// Refund receives the payer's balance and Payout the payee's.
+	Refund *script.Script
+	Payout *script.Script
+	// Fee is the fixed fee paid by every commitment, out of the payer's balance.
+	Fee uint64
+	// LockTime is the lock time of the commitments, the block height or time
+	// after which the latest commitment can be mined without being closed.
+	LockTime uint32
+}
+
+// FundingScript returns the 2-of-2 multisig locking script of the funding output.
+func (p *Params) FundingScript() (*script.Script, error) {
+	return multisig.Lock([]*ec.PublicKey{p.PayerKey, p.PayeeKey}, 2)
+}
+
+// Update is a channel state signed by one party. Paid is the running total
+// paid to the payee and Signature is the signature of the party which
+// issued the update, with the sighash flag appended.
+type Update struct {
+	Sequence  uint32
+	Paid      uint64
+	Signature []byte
+}
+
+// Final reports whether the update closes the channel.
+func (u *Update) Final() bool {
+	return u.Sequence == transaction.MaxTxInSequenceNum
+}
+
+// Channel is one party's view of a payment channel.
+type Channel struct {
+	params  Params
+	key     *ec.PrivateKey
+	payer   bool
+	funding *transaction.TransactionOutput
+	latest  *Update
+	closed  bool
+}
+
+// New opens the channel with the local party's key, which must be the
+// private key of either the payer or the payee.
+func New(params Params, key *ec.PrivateKey) (*Channel, error) {
+	if params.FundingTxID == nil || params.PayerKey == nil || params.PayeeKey == nil ||
+		params.Refund == nil || params.Payout == nil || key == nil {
+		return nil, ErrMissingParams
+	}
+	if params.LockTime == 0 {
+		return nil, ErrNoLockTime
+	}
+	if params.Fee >= params.Capacity {
+		return nil, ErrInsufficientFunds
+	}
+
+	c := &Channel{params: params, key: key}
+	switch pub := key.PubKey(); {
+	case pub.IsEqual(params.PayerKey):
+		c.payer = true
+	case pub.IsEqual(params.PayeeKey):
+	default:
+		return nil, ErrNotParticipant
+	}
+
+	fundingScript, err := params.FundingScript()
+	if err != nil {
+		return nil, err
+	}
+	c.funding = &transaction.TransactionOutput{Satoshis: params.Capacity, LockingScript: fundingScript}
+	return c, nil
+}
+
+// Latest returns the latest state of the channel, or nil before the first payment.
+func (c *Channel) Latest() *Update {
+	return c.latest
+}
+
+// Pay increases the amount paid to the payee and returns the signed update
+// to send to the payee. Only the payer can make payments.
+func (c *Channel) Pay(amount uint64) (*Update, error) {
+	if !c.payer {
+		return nil, ErrNotPayer
+	}
+	if c.closed {
+		return nil, ErrChannelClosed
+	}
+	if amount == 0 {
+		return nil, ErrZeroPayment
+	}
+	u := &Update{Sequence: 1, Paid: amount}
+	if c.latest != nil {
+		u.Sequence = c.latest.Sequence + 1
+		u.Paid = c.latest.Paid + amount
+		if u.Paid < amount {
+			return nil, ErrInsufficientFunds
+		}
+	}
+	if u.Sequence >= transaction.MaxTxInSequenceNum {
+		return nil, ErrSequenceExhausted
+	}
+	if err := c.sign(u); err != nil {
+		return nil, err
+	}
+	c.latest = u
+	return u, nil
+}
+
+// Receive verifies a payment update from the payer against the previous
+// state and, if valid, makes it the latest state.
+func (c *Channel) Receive(u *Update) error {
+	if c.payer {
+		return ErrNotPayee
+	}
+	if c.closed {
+		return ErrChannelClosed
+	}
+	if u.Final() {
+		return ErrUnexpectedFinal
+	}
+	if c.latest != nil {
+		if u.Sequence <= c.latest.Sequence {
+			return ErrStaleSequence
+		}
+		if u.Paid < c.latest.Paid {
+			return ErrDecreasingPayment
+		}
+	}
+	if err := c.verify(u); err != nil {
+		return err
+	}
+	c.latest = u
+	return nil
+}
+
+// Commitment returns the latest commitment transaction signed by both
+// parties. Only the payee holds both signatures. The commitment is not final,
+// so it can only be mined once the lock time has passed, and is replaced by
+// any later commitment or closing transaction.
+func (c *Channel) Commitment() (*transaction.Transaction, error) {
+	if c.payer {
+		return nil, ErrNotPayee
+	}
+	if c.latest == nil {
+		return nil, ErrNoUpdate
+	}
+	local := &Update{Sequence: c.latest.Sequence, Paid: c.latest.Paid}
+	if err := c.sign(local); err != nil {
+		return nil, err
+	}
+	return c.assemble(c.latest, local)
+}
+
+// Close returns a closing update for the latest state, signed by the local
+// party, to be sent to the other party to finalise.
+func (c *Channel) Close() (*Update, error) {
+	if c.closed {
+		return nil, ErrChannelClosed
+	}
+	if c.latest == nil {
+		return nil, ErrNoUpdate
+	}
+	u := &Update{Sequence: transaction.MaxTxInSequenceNum, Paid: c.latest.Paid}
+	if err := c.sign(u); err != nil {
+		return nil, err
+	}
+	c.closed = true
+	return u, nil
+}
+
+// Finalise verifies a closing update from the other party, which must pay
+// the amount of the latest state, co-signs it and returns the final
+// transaction ready to broadcast.
+func (c *Channel) Finalise(u *Update) (*transaction.Transaction, error) {
+	if c.closed {
+		return nil, ErrChannelClosed
+	}
+	if !u.Final() {
+		return nil, ErrNotFinal
+	}
+	if c.latest == nil {
+		return nil, ErrNoUpdate
+	}
+	if u.Paid != c.latest.Paid {
+		return nil, ErrPaymentMismatch
+	}
+	if err := c.verify(u); err != nil {
+		return nil, err
+	}
+	local := &Update{Sequence: u.Sequence, Paid: u.Paid}
+	if err := c.sign(local); err != nil {
+		return nil, err
+	}
+	c.closed = true
+	return c.assemble(u, local)
+}
+
+// build creates the unsigned transaction for a channel state.
+func (c *Channel) build(sequence uint32, paid uint64) (*transaction.Transaction, error) {
+	if paid == 0 {
+		return nil, ErrPayeeOutputTooSmall
+	}
+	if paid+c.params.Fee > c.params.Capacity || paid+c.params.Fee < paid {
+		return nil, ErrInsufficientFunds
+	}
+	tx := transaction.NewTransaction()
+	input := &transaction.TransactionInput{
+		SourceTXID:       c.params.FundingTxID,
+		SourceTxOutIndex: c.params.FundingVout,
+		SequenceNumber:   sequence,
+	}
+	input.SetSourceTxOutput(c.funding)
+	tx.AddInput(input)
+
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: paid, LockingScript: c.params.Payout})
+	if refund := c.params.Capacity - c.params.Fee - paid; refund > 0 {
+		tx.AddOutput(&transaction.TransactionOutput{Satoshis: refund, LockingScript: c.params.Refund})
+	}
+
+	// The closing transaction is final, every commitment waits for the lock time.
+	if sequence != transaction.MaxTxInSequenceNum {
+		tx.LockTime = c.params.LockTime
+	}
+	return tx, nil
+}
+
+func (c *Channel) sighash(u *Update) ([]byte, *transaction.Transaction, error) {
+	tx, err := c.build(u.Sequence, u.Paid)
+	if err != nil {
+		return nil, nil, err
+	}
+	sh, err := tx.CalcInputSignatureHash(0, sighash.AllForkID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sh, tx, nil
+}
+
+func (c *Channel) sign(u *Update) error {
+	sh, _, err := c.sighash(u)
+	if err != nil {
+		return err
+	}
+	sig, err := c.key.Sign(sh)
+	if err != nil {
+		return err
+	}
+	u.Signature = append(sig.Serialize(), uint8(sighash.AllForkID))
+	return nil
+}
+
+// verify checks the update is signed by the other party.
+func (c *Channel) verify(u *Update) error {
+	remote := c.params.PayeeKey
+	if !c.payer {
+		remote = c.params.PayerKey
+	}
+	if len(u.Signature) < 2 || sighash.Flag(u.Signature[len(u.Signature)-1]) != sighash.AllForkID {
+		return ErrInvalidSignature
+	}
+	sig, err := ec.ParseDERSignature(u.Signature[:len(u.Signature)-1])
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	sh, _, err := c.sighash(u)
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(sh, remote) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// assemble builds the transaction for the state signed by both parties,
+// ordering the signatures as the keys appear in the funding script.
+func (c *Channel) assemble(remote, local *Update) (*transaction.Transaction, error) {
+	tx, err := c.build(local.Sequence, local.Paid)
+	if err != nil {
+		return nil, err
+	}
+	payerSig, payeeSig := local.Signature, remote.Signature
+	if !c.payer {
+		payerSig, payeeSig = payeeSig, payerSig
+	}
+	s := &script.Script{}
+	_ = s.AppendOpcodes(script.Op0)
+	for _, sig := range [][]byte{payerSig, payeeSig} {
+		if err = s.AppendPushData(sig); err != nil {
+			return nil, err
+		}
+	}
+	tx.Inputs[0].UnlockingScript = s
+	return tx, nil
+}
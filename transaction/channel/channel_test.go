@@ -0,0 +1,128 @@
+package channel_test
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/channel"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/require"
+)
+
+func newChannels(t *testing.T) (*channel.Channel, *channel.Channel) {
+	payerKey, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	payeeKey, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	lock := func(key *ec.PrivateKey) *script.Script {
+		address, err := script.NewAddressFromPublicKey(key.PubKey(), true)
+		require.NoError(t, err)
+		s, err := p2pkh.Lock(address)
+		require.NoError(t, err)
+		return s
+	}
+
+	fundingTxID := chainhash.DoubleHashH([]byte("funding"))
+	params := channel.Params{
+		FundingTxID: &fundingTxID,
+		Capacity:    10000,
+		PayerKey:    payerKey.PubKey(),
+		PayeeKey:    payeeKey.PubKey(),
+		Refund:      lock(payerKey),
+		Payout:      lock(payeeKey),
+		Fee:         100,
+		LockTime:    900000,
+	}
+
+	payer, err := channel.New(params, payerKey)
+	require.NoError(t, err)
+	payee, err := channel.New(params, payeeKey)
+	require.NoError(t, err)
+	return payer, payee
+}
+
+func requireValid(t *testing.T, tx *transaction.Transaction) {
+	require.NoError(t, interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	))
+}
+
+func TestPaymentChannel(t *testing.T) {
+	payer, payee := newChannels(t)
+
+	var updates []*channel.Update
+	for _, amount := range []uint64{1000, 500, 2500} {
+		u, err := payer.Pay(amount)
+		require.NoError(t, err)
+		require.NoError(t, payee.Receive(u))
+		updates = append(updates, u)
+	}
+	require.Equal(t, uint32(3), payee.Latest().Sequence)
+	require.Equal(t, uint64(4000), payee.Latest().Paid)
+
+	commitment, err := payee.Commitment()
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), commitment.Inputs[0].SequenceNumber)
+	require.Equal(t, uint32(900000), commitment.LockTime)
+	require.Equal(t, uint64(4000), commitment.Outputs[0].Satoshis)
+	require.Equal(t, uint64(5900), commitment.Outputs[1].Satoshis)
+	requireValid(t, commitment)
+
+	// Older states are rejected.
+	require.ErrorIs(t, payee.Receive(updates[1]), channel.ErrStaleSequence)
+
+	closing, err := payee.Close()
+	require.NoError(t, err)
+	final, err := payer.Finalise(closing)
+	require.NoError(t, err)
+	require.Equal(t, uint32(transaction.MaxTxInSequenceNum), final.Inputs[0].SequenceNumber)
+	require.Zero(t, final.LockTime)
+	require.Equal(t, uint64(4000), final.Outputs[0].Satoshis)
+	requireValid(t, final)
+
+	_, err = payer.Pay(100)
+	require.ErrorIs(t, err, channel.ErrChannelClosed)
+
+	// Neither party can close or finalise the channel again.
+	_, err = payee.Close()
+	require.ErrorIs(t, err, channel.ErrChannelClosed)
+	_, err = payer.Close()
+	require.ErrorIs(t, err, channel.ErrChannelClosed)
+	_, err = payer.Finalise(closing)
+	require.ErrorIs(t, err, channel.ErrChannelClosed)
+}
+
+func TestPaymentChannelRejectsBadUpdates(t *testing.T) {
+	payer, payee := newChannels(t)
+
+	u, err := payer.Pay(1000)
+	require.NoError(t, err)
+	require.NoError(t, payee.Receive(u))
+
+	// A higher sequence which pays the payee less.
+	lower := &channel.Update{Sequence: 2, Paid: 500, Signature: u.Signature}
+	require.ErrorIs(t, payee.Receive(lower), channel.ErrDecreasingPayment)
+
+	// A signature which does not cover the claimed state.
+	forged := &channel.Update{Sequence: 2, Paid: 2000, Signature: u.Signature}
+	require.ErrorIs(t, payee.Receive(forged), channel.ErrInvalidSignature)
+
+	_, err = payer.Pay(9000)
+	require.ErrorIs(t, err, channel.ErrInsufficientFunds)
+	_, err = payee.Pay(1)
+	require.ErrorIs(t, err, channel.ErrNotPayer)
+
+	// The payer will only co-sign a close for the agreed amount.
+	closing, err := payee.Close()
+	require.NoError(t, err)
+	closing.Paid = 1500
+	_, err = payer.Finalise(closing)
+	require.ErrorIs(t, err, channel.ErrPaymentMismatch)
+}
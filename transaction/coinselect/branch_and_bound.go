@@ -0,0 +1,102 @@
+package coinselect
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// DefaultMaxTries is the number of branches BranchAndBound
+// explores when MaxTries is not set.
+const DefaultMaxTries = 100000
+
+// BranchAndBound searches for a set of UTXOs which covers the outputs and the
+// fee exactly, so that no change output is needed. A selection is considered
+// an exact match when its excess over the outputs and fee does not exceed
+// CostOfChange. It is meant for transactions without change outputs, where
+// that excess is given up to the miners and counted in the Result's Fee; if
+// the transaction has change outputs the excess is paid to them instead.
+type BranchAndBound struct {
+	// CostOfChange is the largest excess accepted for a match.
+	CostOfChange uint64
+	// MaxTries bounds the number of branches explored.
+	MaxTries int
+	// Fallback is used when no match is found. If nil, ErrNoExactMatch is returned.
+	Fallback Selector
+}
+
+func (s *BranchAndBound) Select(tx *transaction.Transaction, utxos transaction.UTXOs,
+	feeModel transaction.FeeModel) (*Result, error) {
+	f, err := newFunding(tx, feeModel)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := slices.Clone(utxos)
+	slices.SortStableFunc(ordered, func(a, b *transaction.UTXO) int {
+		return cmp.Compare(b.Satoshis, a.Satoshis)
+	})
+	remaining := make([]uint64, len(ordered)+1)
+	for i := len(ordered) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + ordered[i].Satoshis
+	}
+
+	maxTries := s.MaxTries
+	if maxTries <= 0 {
+		maxTries = DefaultMaxTries
+	}
+
+	var best transaction.UTXOs
+	bestExcess := uint64(math.MaxUint64)
+	tries := 0
+
+	var search func(i int, selected transaction.UTXOs, total uint64) error
+	search = func(i int, selected transaction.UTXOs, total uint64) error {
+		if tries >= maxTries || bestExcess == 0 {
+			return nil
+		}
+		tries++
+
+		fee, err := f.fee(selected)
+		if err != nil {
+			return err
+		}
+		if total >= f.target+fee {
+			// Adding further inputs can only increase the excess.
+			if excess := total - f.target - fee; excess <= s.CostOfChange && excess < bestExcess {
+				best = slices.Clone(selected)
+				bestExcess = excess
+			}
+			return nil
+		}
+		if total+remaining[i] < f.target+fee {
+			return nil
+		}
+
+		if err = search(i+1, append(selected, ordered[i]), total+ordered[i].Satoshis); err != nil {
+			return err
+		}
+
+		// Skip UTXOs of equal value when excluding, as those
+		// selections are equivalent to ones already explored.
+		next := i + 1
+		for next < len(ordered) && ordered[next].Satoshis == ordered[i].Satoshis {
+			next++
+		}
+		return search(next, selected, total)
+	}
+
+	if err = search(0, make(transaction.UTXOs, 0, len(ordered)), f.existing); err != nil {
+		return nil, err
+	}
+
+	if best == nil {
+		if s.Fallback != nil {
+			return s.Fallback.Select(tx, utxos, feeModel)
+		}
+		return nil, ErrNoExactMatch
+	}
+	return f.result(best)
+}
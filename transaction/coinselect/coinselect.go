@@ -0,0 +1,163 @@
+package coinselect
+
+import (
+	"errors"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+var (
+	ErrNoExactMatch = errors.New("no exact match found")
+	ErrNoFeeModel   = errors.New("fee model not supplied")
+)
+
+// Result is the outcome of a coin selection.
+type Result struct {
+	// Inputs are the UTXOs selected to fund the transaction.
+	Inputs transaction.UTXOs
+	// Change is the amount paid to the transaction's change outputs. It is
+	// zero when the transaction has no change outputs, or too little is
+	// left over to give each one a satoshi, as the excess then goes to the
+	// miners.
+	Change uint64
+	// Fee is the fee the funded transaction pays: the fee computed by the
+	// fee model plus any excess which is not paid as change.
+	Fee uint64
+}
+
+// Selector picks UTXOs to fund a transaction.
+//
+// The target is the total of the transaction's non-change outputs, less
+// anything already provided by its existing inputs. Each candidate
+// selection is sized by the fee model, which relies on the
+// UnlockingScriptTemplate of every UTXO to estimate its unlocking script.
+type Selector interface {
+	Select(tx *transaction.Transaction, utxos transaction.UTXOs, feeModel transaction.FeeModel) (*Result, error)
+}
+
+// Fund selects UTXOs using the provided selector, adds them as inputs to the
+// transaction and distributes any change equally across the change outputs.
+// The change and fee of the result are those of the funded transaction.
+func Fund(tx *transaction.Transaction, utxos transaction.UTXOs, feeModel transaction.FeeModel,
+	s Selector) (*Result, error) {
+	res, err := s.Select(tx, utxos, feeModel)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.AddInputsFromUTXOs(res.Inputs...); err != nil {
+		return nil, err
+	}
+	if err = tx.Fee(feeModel, transaction.ChangeDistributionEqual); err != nil {
+		return nil, err
+	}
+	if res.Fee, err = tx.GetFee(); err != nil {
+		return nil, err
+	}
+	res.Change = 0
+	for _, o := range tx.Outputs {
+		if o.Change {
+			res.Change += o.Satoshis
+		}
+	}
+	return res, nil
+}
+
+// funding holds the state shared by the selectors while
+// evaluating candidate selections for a transaction.
+type funding struct {
+	tx         *transaction.Transaction
+	feeModel   transaction.FeeModel
+	target     uint64
+	existing   uint64
+	changeOuts uint64
+}
+
+func newFunding(tx *transaction.Transaction, feeModel transaction.FeeModel) (*funding, error) {
+	if tx == nil {
+		return nil, transaction.ErrTxNil
+	}
+	if feeModel == nil {
+		return nil, ErrNoFeeModel
+	}
+	existing, err := tx.TotalInputSatoshis()
+	if err != nil {
+		return nil, err
+	}
+	var target, changeOuts uint64
+	for _, o := range tx.Outputs {
+		if o.Change {
+			changeOuts++
+		} else {
+			target += o.Satoshis
+		}
+	}
+	return &funding{
+		tx:         tx,
+		feeModel:   feeModel,
+		target:     target,
+		existing:   existing,
+		changeOuts: changeOuts,
+	}, nil
+}
+
+// fee computes the fee of the transaction with the selected UTXOs added as inputs.
+func (f *funding) fee(selected transaction.UTXOs) (uint64, error) {
+	candidate := &transaction.Transaction{
+		Version:  f.tx.Version,
+		LockTime: f.tx.LockTime,
+		Inputs:   make([]*transaction.TransactionInput, len(f.tx.Inputs), len(f.tx.Inputs)+len(selected)),
+		Outputs:  f.tx.Outputs,
+	}
+	copy(candidate.Inputs, f.tx.Inputs)
+	if err := candidate.AddInputsFromUTXOs(selected...); err != nil {
+		return 0, err
+	}
+	return f.feeModel.ComputeFee(candidate)
+}
+
+// result returns the result for the selected UTXOs, or
+// ErrInsufficientFunds if they do not cover the outputs and the fee.
+func (f *funding) result(selected transaction.UTXOs) (*Result, error) {
+	fee, err := f.fee(selected)
+	if err != nil {
+		return nil, err
+	}
+	total := f.existing + sum(selected)
+	if total < f.target+fee {
+		return nil, transaction.ErrInsufficientFunds
+	}
+	// Split the excess the way Transaction.Fee does with
+	// ChangeDistributionEqual, which leaves any remainder to the miners.
+	var change uint64
+	if excess := total - f.target - fee; f.changeOuts > 0 && excess >= f.changeOuts {
+		change = excess / f.changeOuts * f.changeOuts
+	}
+	return &Result{
+		Inputs: selected,
+		Change: change,
+		Fee:    total - f.target - change,
+	}, nil
+}
+
+// accumulate adds UTXOs in the given order until the outputs and fee are covered.
+func (f *funding) accumulate(ordered transaction.UTXOs) (*Result, error) {
+	selected := make(transaction.UTXOs, 0)
+	total := f.existing
+	for _, u := range ordered {
+		if fee, err := f.fee(selected); err != nil {
+			return nil, err
+		} else if total >= f.target+fee {
+			break
+		}
+		selected = append(selected, u)
+		total += u.Satoshis
+	}
+	return f.result(selected)
+}
+
+func sum(utxos transaction.UTXOs) (total uint64) {
+	for _, u := range utxos {
+		total += u.Satoshis
+	}
+	return
+}
@@ -0,0 +1,176 @@
+package coinselect_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/coinselect"
+	feemodel "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/require"
+)
+
+var feeModel = &feemodel.SatoshisPerKilobyte{Satoshis: 50}
+
+func newUTXOs(t *testing.T, priv *ec.PrivateKey, amounts ...uint64) transaction.UTXOs {
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+	lockingScript, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	unlocker, err := p2pkh.Unlock(priv, nil)
+	require.NoError(t, err)
+
+	utxos := make(transaction.UTXOs, 0, len(amounts))
+	for i, amount := range amounts {
+		txid := chainhash.DoubleHashH([]byte{byte(i)})
+		utxos = append(utxos, &transaction.UTXO{
+			TxID:                    &txid,
+			Vout:                    uint32(i),
+			LockingScript:           lockingScript,
+			Satoshis:                amount,
+			UnlockingScriptTemplate: unlocker,
+		})
+	}
+	return utxos
+}
+
+func newPayment(t *testing.T, satoshis uint64, withChange bool) *transaction.Transaction {
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", satoshis))
+	if withChange {
+		require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 0))
+		tx.Outputs[1].Change = true
+	}
+	return tx
+}
+
+func requireCovers(t *testing.T, res *coinselect.Result, target uint64) {
+	var total uint64
+	for _, u := range res.Inputs {
+		total += u.Satoshis
+	}
+	require.Equal(t, total, target+res.Fee+res.Change)
+}
+
+func TestLargestFirst(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	utxos := newUTXOs(t, priv, 1000, 50000, 3000, 20000)
+
+	res, err := (&coinselect.LargestFirst{}).Select(newPayment(t, 60000, true), utxos, feeModel)
+	require.NoError(t, err)
+	require.Len(t, res.Inputs, 2)
+	require.Equal(t, uint64(50000), res.Inputs[0].Satoshis)
+	require.Equal(t, uint64(20000), res.Inputs[1].Satoshis)
+	requireCovers(t, res, 60000)
+
+	_, err = (&coinselect.LargestFirst{}).Select(newPayment(t, 80000, true), utxos, feeModel)
+	require.ErrorIs(t, err, transaction.ErrInsufficientFunds)
+}
+
+func TestOldestFirst(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	utxos := newUTXOs(t, priv, 1000, 50000, 3000, 20000)
+
+	res, err := (&coinselect.OldestFirst{}).Select(newPayment(t, 2000, true), utxos, feeModel)
+	require.NoError(t, err)
+	require.Len(t, res.Inputs, 2)
+	require.Equal(t, uint64(1000), res.Inputs[0].Satoshis)
+	require.Equal(t, uint64(50000), res.Inputs[1].Satoshis)
+
+	heights := map[uint32]uint32{0: 40, 1: 30, 2: 10, 3: 20}
+	res, err = (&coinselect.OldestFirst{
+		Height: func(u *transaction.UTXO) uint32 { return heights[u.Vout] },
+	}).Select(newPayment(t, 2000, true), utxos, feeModel)
+	require.NoError(t, err)
+	require.Len(t, res.Inputs, 1)
+	require.Equal(t, uint64(3000), res.Inputs[0].Satoshis)
+	requireCovers(t, res, 2000)
+}
+
+func TestBranchAndBound(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	utxos := newUTXOs(t, priv, 7000, 4000, 3000, 2000, 900)
+
+	// Two P2PKH inputs and one output come to a fee of 50 satoshis.
+	tx := newPayment(t, 4950, false)
+	res, err := (&coinselect.BranchAndBound{}).Select(tx, utxos, feeModel)
+	require.NoError(t, err)
+	require.Len(t, res.Inputs, 2)
+	require.Equal(t, uint64(3000), res.Inputs[0].Satoshis)
+	require.Equal(t, uint64(2000), res.Inputs[1].Satoshis)
+	require.Zero(t, res.Change)
+	require.Equal(t, uint64(50), res.Fee)
+
+	_, err = (&coinselect.BranchAndBound{}).Select(newPayment(t, 4000, false), utxos, feeModel)
+	require.ErrorIs(t, err, coinselect.ErrNoExactMatch)
+
+	// Without a change output the excess goes to the miners.
+	res, err = (&coinselect.BranchAndBound{CostOfChange: 100}).Select(newPayment(t, 4870, false), utxos, feeModel)
+	require.NoError(t, err)
+	require.Zero(t, res.Change)
+	require.Equal(t, uint64(130), res.Fee)
+	requireCovers(t, res, 4870)
+
+	res, err = (&coinselect.BranchAndBound{Fallback: &coinselect.LargestFirst{}}).Select(
+		newPayment(t, 4000, false), utxos, feeModel)
+	require.NoError(t, err)
+	require.Len(t, res.Inputs, 1)
+	require.Equal(t, uint64(7000), res.Inputs[0].Satoshis)
+}
+
+func TestKnapsack(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	utxos := newUTXOs(t, priv, 100, 200, 300, 400, 500, 600, 700, 800, 900, 100000)
+
+	selector := &coinselect.Knapsack{Rand: rand.New(rand.NewPCG(1, 2))}
+	res, err := selector.Select(newPayment(t, 2000, true), utxos, feeModel)
+	require.NoError(t, err)
+	requireCovers(t, res, 2000)
+	for _, u := range res.Inputs {
+		require.NotEqual(t, uint64(100000), u.Satoshis)
+	}
+
+	res, err = selector.Select(newPayment(t, 10000, true), utxos, feeModel)
+	require.NoError(t, err)
+	require.Len(t, res.Inputs, 1)
+	require.Equal(t, uint64(100000), res.Inputs[0].Satoshis)
+
+	_, err = selector.Select(newPayment(t, 200000, true), utxos, feeModel)
+	require.ErrorIs(t, err, transaction.ErrInsufficientFunds)
+}
+
+func TestFund(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	utxos := newUTXOs(t, priv, 1000, 50000, 3000, 20000)
+
+	tx := newPayment(t, 30000, true)
+	res, err := coinselect.Fund(tx, utxos, feeModel, &coinselect.LargestFirst{})
+	require.NoError(t, err)
+	require.Len(t, tx.Inputs, 1)
+	require.Equal(t, res.Change, tx.Outputs[1].Satoshis)
+
+	require.NoError(t, tx.Sign())
+	fee, err := tx.GetFee()
+	require.NoError(t, err)
+	require.Equal(t, res.Fee, fee)
+
+	// The excess of an exact match is paid to the change output when there
+	// is one.
+	tx = newPayment(t, 4870, true)
+	res, err = coinselect.Fund(tx, newUTXOs(t, priv, 7000, 4000, 3000, 2000, 900), feeModel,
+		&coinselect.BranchAndBound{CostOfChange: 200})
+	require.NoError(t, err)
+	require.Len(t, tx.Outputs, 2)
+	require.Equal(t, tx.Outputs[1].Satoshis, res.Change)
+	require.NotZero(t, res.Change)
+	requireCovers(t, res, 4870)
+}
@@ -0,0 +1,151 @@
+package coinselect
+
+import (
+	"cmp"
+	"errors"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// DefaultKnapsackIterations is the number of random subsets Knapsack
+// evaluates when Iterations is not set.
+const DefaultKnapsackIterations = 1000
+
+// Knapsack selects UTXOs using a stochastic approximation of the subset
+// whose total most closely exceeds the outputs and fee. It prefers a single
+// UTXO matching the amount exactly and otherwise compares the best random
+// subset of smaller UTXOs against the smallest UTXO that covers the amount
+// on its own.
+type Knapsack struct {
+	// Iterations is the number of random subsets evaluated.
+	Iterations int
+	// Rand is the source of randomness. If nil, a random seed is used.
+	Rand *rand.Rand
+}
+
+func (s *Knapsack) Select(tx *transaction.Transaction, utxos transaction.UTXOs,
+	feeModel transaction.FeeModel) (*Result, error) {
+	f, err := newFunding(tx, feeModel)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := f.fee(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The fee depends on the inputs selected, so repeat the
+	// selection until the chosen inputs also cover their own fee.
+	for attempt := 0; attempt <= len(utxos); attempt++ {
+		if f.existing >= f.target+fee {
+			return f.result(nil)
+		}
+		selected := s.knapsack(utxos, f.target+fee-f.existing)
+		if selected == nil {
+			return nil, transaction.ErrInsufficientFunds
+		}
+		res, err := f.result(selected)
+		if err == nil {
+			return res, nil
+		} else if !errors.Is(err, transaction.ErrInsufficientFunds) {
+			return nil, err
+		}
+		if fee, err = f.fee(selected); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, transaction.ErrInsufficientFunds
+}
+
+func (s *Knapsack) knapsack(utxos transaction.UTXOs, need uint64) transaction.UTXOs {
+	var lowestLarger *transaction.UTXO
+	smaller := make(transaction.UTXOs, 0, len(utxos))
+	var smallerTotal uint64
+	for _, u := range utxos {
+		switch {
+		case u.Satoshis == need:
+			return transaction.UTXOs{u}
+		case u.Satoshis < need:
+			smaller = append(smaller, u)
+			smallerTotal += u.Satoshis
+		case lowestLarger == nil || u.Satoshis < lowestLarger.Satoshis:
+			lowestLarger = u
+		}
+	}
+
+	if smallerTotal == need {
+		return smaller
+	}
+	if smallerTotal < need {
+		if lowestLarger == nil {
+			return nil
+		}
+		return transaction.UTXOs{lowestLarger}
+	}
+
+	slices.SortStableFunc(smaller, func(a, b *transaction.UTXO) int {
+		return cmp.Compare(b.Satoshis, a.Satoshis)
+	})
+	best, bestTotal := s.approximateBestSubset(smaller, smallerTotal, need)
+	if lowestLarger != nil && bestTotal != need && lowestLarger.Satoshis <= bestTotal {
+		return transaction.UTXOs{lowestLarger}
+	}
+	return best
+}
+
+// approximateBestSubset randomly includes UTXOs over a number of iterations,
+// keeping the subset with the lowest total that still reaches the target.
+func (s *Knapsack) approximateBestSubset(utxos transaction.UTXOs, total, target uint64) (transaction.UTXOs, uint64) {
+	iterations := s.Iterations
+	if iterations <= 0 {
+		iterations = DefaultKnapsackIterations
+	}
+	randBool := func() bool { return rand.IntN(2) == 0 }
+	if s.Rand != nil {
+		randBool = func() bool { return s.Rand.IntN(2) == 0 }
+	}
+
+	best := make([]bool, len(utxos))
+	for i := range best {
+		best[i] = true
+	}
+	bestTotal := total
+
+	included := make([]bool, len(utxos))
+	for rep := 0; rep < iterations && bestTotal != target; rep++ {
+		clear(included)
+		var runningTotal uint64
+		reached := false
+		for pass := 0; pass < 2 && !reached; pass++ {
+			for i, u := range utxos {
+				// The first pass includes UTXOs at random, the
+				// second adds any not yet included.
+				if (pass == 0 && randBool()) || (pass == 1 && !included[i]) {
+					runningTotal += u.Satoshis
+					included[i] = true
+					if runningTotal >= target {
+						reached = true
+						if runningTotal < bestTotal {
+							bestTotal = runningTotal
+							copy(best, included)
+						}
+						runningTotal -= u.Satoshis
+						included[i] = false
+					}
+				}
+			}
+		}
+	}
+
+	subset := make(transaction.UTXOs, 0, len(utxos))
+	for i, u := range utxos {
+		if best[i] {
+			subset = append(subset, u)
+		}
+	}
+	return subset, bestTotal
+}
@@ -0,0 +1,26 @@
+package coinselect
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// LargestFirst selects the largest UTXOs first until the outputs and fee are
+// covered. It minimises the number of inputs at the cost of consolidating
+// small UTXOs more slowly.
+type LargestFirst struct{}
+
+func (s *LargestFirst) Select(tx *transaction.Transaction, utxos transaction.UTXOs,
+	feeModel transaction.FeeModel) (*Result, error) {
+	f, err := newFunding(tx, feeModel)
+	if err != nil {
+		return nil, err
+	}
+	ordered := slices.Clone(utxos)
+	slices.SortStableFunc(ordered, func(a, b *transaction.UTXO) int {
+		return cmp.Compare(b.Satoshis, a.Satoshis)
+	})
+	return f.accumulate(ordered)
+}
@@ -0,0 +1,31 @@
+package coinselect
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// OldestFirst selects UTXOs in order of age until the outputs and fee are
+// covered. If Height is nil the UTXOs are assumed to already be ordered
+// from oldest to newest.
+type OldestFirst struct {
+	// Height returns the block height at which a UTXO was mined.
+	Height func(u *transaction.UTXO) uint32
+}
+
+func (s *OldestFirst) Select(tx *transaction.Transaction, utxos transaction.UTXOs,
+	feeModel transaction.FeeModel) (*Result, error) {
+	f, err := newFunding(tx, feeModel)
+	if err != nil {
+		return nil, err
+	}
+	ordered := slices.Clone(utxos)
+	if s.Height != nil {
+		slices.SortStableFunc(ordered, func(a, b *transaction.UTXO) int {
+			return cmp.Compare(s.Height(a), s.Height(b))
+		})
+	}
+	return f.accumulate(ordered)
+}
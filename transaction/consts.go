@@ -22,4 +22,15 @@ const (
 	// SequenceLockTimeMask is a mask that extracts the relative locktime
 	// when masked against the transaction input sequence number.
 	SequenceLockTimeMask = 0x0000ffff
+
+	// MaxSignaturePushLength is the worst case length of a signature in an
+	// unlocking script, for estimating its size before signing: a push of a
+	// low-S DER signature of at most 71 bytes with the sighash flag,
+	// 1+71+1 = 73 bytes.
+	MaxSignaturePushLength = 73
+
+	// P2PKHUnlockingScriptLength is the worst case length of a P2PKH
+	// unlocking script: a signature push and the push of a compressed
+	// public key, 73+1+33 = 107 bytes.
+	P2PKHUnlockingScriptLength = MaxSignaturePushLength + 1 + 33
 )
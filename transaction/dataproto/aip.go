@@ -0,0 +1,127 @@
+package dataproto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+
+	bsm "github.com/bsv-blockchain/go-sdk/compat/bsm"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+)
+
+// AIPAlgorithm is the signing algorithm of AIP records, a Bitcoin signed message.
+const AIPAlgorithm = "BITCOIN_ECDSA"
+
+// AIP is an Author Identity Protocol record, which signs the data before it
+// in the output with the key of Address.
+//
+// <AIP prefix> BITCOIN_ECDSA <address> <base64 signature> [<index> ...]
+//
+// The signed message is the concatenation of the OP_RETURN opcode and every
+// push preceding the AIP prefix, including the separator before it. If
+// Indexes are given only the fields at those positions are signed, where
+// the OP_RETURN is at 0 and the pushes follow from 1.
+type AIP struct {
+	Algorithm string
+	Address   string
+	Signature []byte
+	Indexes   []int
+	// Valid is set by Decode when the signature matches the address.
+	Valid bool
+}
+
+// SignAIP signs the records with the key and returns the AIP record to
+// append after them.
+func SignAIP(key *ec.PrivateKey, records ...Record) (*AIP, error) {
+	if key == nil {
+		return nil, ErrNoPrivateKey
+	}
+	pushes, err := Pushes(records...)
+	if err != nil {
+		return nil, err
+	}
+	signed := append([][]byte{{script.OpRETURN}}, pushes...)
+	signed = append(signed, []byte(Separator))
+
+	address, err := script.NewAddressFromPublicKey(key.PubKey(), true)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := bsm.SignMessage(key, bytes.Join(signed, nil))
+	if err != nil {
+		return nil, err
+	}
+	return &AIP{
+		Algorithm: AIPAlgorithm,
+		Address:   address.AddressString,
+		Signature: sig,
+		Valid:     true,
+	}, nil
+}
+
+func (a *AIP) Prefix() string {
+	return AIPPrefix
+}
+
+func (a *AIP) Fields() ([][]byte, error) {
+	if a.Address == "" || len(a.Signature) == 0 {
+		return nil, ErrBadAIP
+	}
+	algorithm := a.Algorithm
+	if algorithm == "" {
+		algorithm = AIPAlgorithm
+	}
+	fields := [][]byte{
+		[]byte(algorithm),
+		[]byte(a.Address),
+		[]byte(base64.StdEncoding.EncodeToString(a.Signature)),
+	}
+	for _, idx := range a.Indexes {
+		fields = append(fields, []byte(strconv.Itoa(idx)))
+	}
+	return fields, nil
+}
+
+// Verify checks the signature against the fields preceding the record,
+// starting with the OP_RETURN opcode.
+func (a *AIP) Verify(signed [][]byte) error {
+	if a.Algorithm != AIPAlgorithm {
+		return ErrBadAIP
+	}
+	message := signed
+	if len(a.Indexes) > 0 {
+		message = make([][]byte, 0, len(a.Indexes))
+		for _, idx := range a.Indexes {
+			if idx < 0 || idx >= len(signed) {
+				return ErrBadAIPIndex
+			}
+			message = append(message, signed[idx])
+		}
+	}
+	return bsm.VerifyMessage(a.Address, a.Signature, bytes.Join(message, nil))
+}
+
+func decodeAIP(fields [][]byte, signed [][]byte) (*AIP, error) {
+	if len(fields) < 3 {
+		return nil, ErrBadAIP
+	}
+	sig, err := base64.StdEncoding.DecodeString(string(fields[2]))
+	if err != nil {
+		return nil, ErrBadAIP
+	}
+	a := &AIP{
+		Algorithm: string(fields[0]),
+		Address:   string(fields[1]),
+		Signature: sig,
+	}
+	for _, f := range fields[3:] {
+		idx, err := strconv.Atoi(string(f))
+		if err != nil {
+			return nil, ErrBadAIPIndex
+		}
+		a.Indexes = append(a.Indexes, idx)
+	}
+	a.Valid = a.Verify(signed) == nil
+	return a, nil
+}
@@ -0,0 +1,51 @@
+package dataproto
+
+// B is a B:// record, which stores a file on chain.
+//
+// <B prefix> <data> <media type> [<encoding> [<filename>]]
+type B struct {
+	Data      []byte
+	MediaType string
+	// Encoding is optional, such as "binary" or "utf-8".
+	Encoding string
+	// Filename is optional.
+	Filename string
+}
+
+func (b *B) Prefix() string {
+	return BPrefix
+}
+
+func (b *B) Fields() ([][]byte, error) {
+	if b.MediaType == "" {
+		return nil, ErrNoMediaType
+	}
+	fields := [][]byte{b.Data, []byte(b.MediaType)}
+	if b.Encoding == "" && b.Filename == "" {
+		return fields, nil
+	}
+	encoding := b.Encoding
+	if encoding == "" {
+		// The encoding must be present for the filename to follow it.
+		encoding = "binary"
+	}
+	fields = append(fields, []byte(encoding))
+	if b.Filename != "" {
+		fields = append(fields, []byte(b.Filename))
+	}
+	return fields, nil
+}
+
+func decodeB(fields [][]byte) (*B, error) {
+	if len(fields) < 2 || len(fields[1]) == 0 {
+		return nil, ErrNoMediaType
+	}
+	b := &B{Data: fields[0], MediaType: string(fields[1])}
+	if len(fields) > 2 {
+		b.Encoding = string(fields[2])
+	}
+	if len(fields) > 3 {
+		b.Filename = string(fields[3])
+	}
+	return b, nil
+}
@@ -0,0 +1,194 @@
+// Package dataproto builds and parses OP_RETURN outputs using the pipe
+// delimited Bitcoin data protocols:
+//
+//   - B:// stores a file
+//   - MAP attaches key value metadata
+//   - AIP signs the preceding data with a Bitcoin signed message
+//
+// An output holds one or more records, each starting with the protocol's
+// prefix and separated by a "|" push:
+//
+//	OP_FALSE OP_RETURN <B prefix> <data> <media type> ... | <MAP prefix> SET <key> <value> ... | <AIP prefix> ...
+//
+// A "|" push only separates records when it is followed by a protocol
+// prefix, so a field whose data is "|" is kept as data.
+package dataproto
+
+import (
+	"errors"
+
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+const (
+	// BPrefix is the prefix of B:// records.
+	BPrefix = "19HxigV4QyBv3tHpQVcUEQyq1pzZVdoAut"
+	// MAPPrefix is the prefix of MAP records.
+	MAPPrefix = "1PuQa7K62MiKCtssSLKy1kh56WWU7MtUR5"
+	// AIPPrefix is the prefix of AIP records.
+	AIPPrefix = "15PciHG22SNLQJXMoSUaWVi7WSqc7hCfva"
+	// Separator separates the records of an output.
+	Separator = "|"
+)
+
+var (
+	ErrNoRecords      = errors.New("no records supplied")
+	ErrNotDataOutput  = errors.New("script is not an OP_RETURN output")
+	ErrEmptyRecord    = errors.New("record has no prefix")
+	ErrNoMediaType    = errors.New("b record must have a media type")
+	ErrBadMAPPairs    = errors.New("map record must have a value for every key")
+	ErrBadAIP         = errors.New("malformed aip record")
+	ErrBadAIPIndex    = errors.New("aip index does not refer to a signed field")
+	ErrNoPrivateKey   = errors.New("private key not supplied")
+	ErrUnknownCommand = errors.New("unknown map command")
+)
+
+// Record is a record of a data protocol.
+type Record interface {
+	// Prefix returns the protocol prefix of the record.
+	Prefix() string
+	// Fields returns the pushes of the record which follow its prefix.
+	Fields() ([][]byte, error)
+}
+
+// Unknown is a record of a protocol this package does not decode.
+type Unknown struct {
+	Protocol string
+	Data     [][]byte
+}
+
+func (u *Unknown) Prefix() string {
+	return u.Protocol
+}
+
+func (u *Unknown) Fields() ([][]byte, error) {
+	return u.Data, nil
+}
+
+// Pushes returns the data pushed after OP_FALSE OP_RETURN for the records.
+func Pushes(records ...Record) ([][]byte, error) {
+	if len(records) == 0 {
+		return nil, ErrNoRecords
+	}
+	var pushes [][]byte
+	for i, r := range records {
+		if r.Prefix() == "" {
+			return nil, ErrEmptyRecord
+		}
+		fields, err := r.Fields()
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			pushes = append(pushes, []byte(Separator))
+		}
+		pushes = append(pushes, []byte(r.Prefix()))
+		pushes = append(pushes, fields...)
+	}
+	return pushes, nil
+}
+
+// Lock returns the OP_FALSE OP_RETURN locking script holding the records.
+func Lock(records ...Record) (*script.Script, error) {
+	pushes, err := Pushes(records...)
+	if err != nil {
+		return nil, err
+	}
+	s := &script.Script{}
+	_ = s.AppendOpcodes(script.OpFALSE, script.OpRETURN)
+	if err = s.AppendPushDataArray(pushes); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewOutput returns a zero satoshi output holding the records.
+func NewOutput(records ...Record) (*transaction.TransactionOutput, error) {
+	s, err := Lock(records...)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction.TransactionOutput{LockingScript: s}, nil
+}
+
+// Decode parses the records of an OP_RETURN output. B://, MAP and AIP
+// records are returned as *B, *MAP and *AIP, any others as *Unknown. The
+// signatures of AIP records are verified against the data preceding them,
+// see AIP.Valid.
+func Decode(s *script.Script) ([]Record, error) {
+	if s == nil || !s.IsData() {
+		return nil, ErrNotDataOutput
+	}
+	b := []byte(*s)
+	if b[0] == script.OpFALSE {
+		b = b[1:]
+	}
+	chunks, err := script.DecodeScript(b[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	// signed holds the OP_RETURN and every push so far, which AIP signs.
+	signed := [][]byte{{script.OpRETURN}}
+	start := 0
+	for i := 0; i <= len(chunks); i++ {
+		if i < len(chunks) && !separates(chunks, i) {
+			continue
+		}
+		if i > start {
+			fields := make([][]byte, 0, i-start)
+			for _, c := range chunks[start:i] {
+				fields = append(fields, c.Data)
+			}
+			r, err := decodeRecord(fields, signed)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, r)
+			signed = append(signed, fields...)
+		}
+		if i < len(chunks) {
+			signed = append(signed, chunks[i].Data)
+		}
+		start = i + 1
+	}
+	return records, nil
+}
+
+// separates reports whether the push at i separates two records: it is "|"
+// and the push after it is a protocol prefix.
+func separates(chunks []*script.ScriptChunk, i int) bool {
+	return string(chunks[i].Data) == Separator && i+1 < len(chunks) && isPrefix(chunks[i+1].Data)
+}
+
+// isPrefix reports whether b is a protocol prefix. Protocols are identified
+// by a Bitcoin address, so any valid address is accepted, not only the
+// prefixes this package decodes.
+func isPrefix(b []byte) bool {
+	switch string(b) {
+	case BPrefix, MAPPrefix, AIPPrefix:
+		return true
+	}
+	_, err := script.NewAddressFromString(string(b))
+	return err == nil
+}
+
+// decodeRecord decodes a record from its prefix and fields.
+func decodeRecord(fields [][]byte, signed [][]byte) (Record, error) {
+	prefix, fields := string(fields[0]), fields[1:]
+	switch prefix {
+	case BPrefix:
+		return decodeB(fields)
+	case MAPPrefix:
+		m, err := decodeMAP(fields)
+		if errors.Is(err, ErrUnknownCommand) {
+			return &Unknown{Protocol: prefix, Data: fields}, nil
+		}
+		return m, err
+	case AIPPrefix:
+		return decodeAIP(fields, signed)
+	}
+	return &Unknown{Protocol: prefix, Data: fields}, nil
+}
@@ -0,0 +1,99 @@
+package dataproto_test
+
+import (
+	"testing"
+
+	bsm "github.com/bsv-blockchain/go-sdk/compat/bsm"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction/dataproto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	b := &dataproto.B{Data: []byte("# Hello"), MediaType: "text/markdown", Encoding: "utf-8", Filename: "hello.md"}
+	m, err := dataproto.NewMAP("app", "example", "type", "post")
+	require.NoError(t, err)
+	aip, err := dataproto.SignAIP(key, b, m)
+	require.NoError(t, err)
+
+	output, err := dataproto.NewOutput(b, m, aip)
+	require.NoError(t, err)
+	require.True(t, output.LockingScript.IsData())
+	require.Zero(t, output.Satoshis)
+
+	records, err := dataproto.Decode(output.LockingScript)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, b, records[0])
+	require.Equal(t, m, records[1])
+	decoded := records[2].(*dataproto.AIP)
+	require.True(t, decoded.Valid)
+	require.Equal(t, aip.Address, decoded.Address)
+
+	value, ok := records[1].(*dataproto.MAP).Get("type")
+	require.True(t, ok)
+	require.Equal(t, "post", value)
+}
+
+func TestDecodeTamperedAndUnknown(t *testing.T) {
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	b := &dataproto.B{Data: []byte("hello"), MediaType: "text/plain"}
+	aip, err := dataproto.SignAIP(key, b)
+	require.NoError(t, err)
+
+	// The signature no longer covers the data.
+	tampered := &dataproto.B{Data: []byte("hellO"), MediaType: "text/plain"}
+	other := &dataproto.Unknown{Protocol: "1BAPSuaPnfGnSBM3GLV9yhxUdYe4vGbdMT", Data: [][]byte{[]byte("ID")}}
+	s, err := dataproto.Lock(tampered, aip, other)
+	require.NoError(t, err)
+
+	records, err := dataproto.Decode(s)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.False(t, records[1].(*dataproto.AIP).Valid)
+	require.Equal(t, other, records[2])
+
+	// Signing only the B data and media type, which follow the OP_RETURN and prefix.
+	address, err := script.NewAddressFromPublicKey(key.PubKey(), true)
+	require.NoError(t, err)
+	sig, err := bsm.SignMessage(key, []byte("hellotext/plain"))
+	require.NoError(t, err)
+	partial := &dataproto.AIP{Algorithm: dataproto.AIPAlgorithm, Address: address.AddressString, Signature: sig, Indexes: []int{2, 3}}
+	s, err = dataproto.Lock(b, &dataproto.MAP{Pairs: []dataproto.KeyValue{{Key: "app", Value: "example"}}}, partial)
+	require.NoError(t, err)
+	records, err = dataproto.Decode(s)
+	require.NoError(t, err)
+	require.True(t, records[2].(*dataproto.AIP).Valid)
+	require.Equal(t, []int{2, 3}, records[2].(*dataproto.AIP).Indexes)
+
+	_, err = dataproto.Decode(script.NewFromBytes([]byte{script.OpTRUE}))
+	require.ErrorIs(t, err, dataproto.ErrNotDataOutput)
+	_, err = dataproto.NewMAP("app")
+	require.ErrorIs(t, err, dataproto.ErrBadMAPPairs)
+	_, err = dataproto.Lock(&dataproto.B{Data: []byte("x")})
+	require.ErrorIs(t, err, dataproto.ErrNoMediaType)
+}
+
+func TestDecodePipeData(t *testing.T) {
+	// Fields whose data is "|" are not mistaken for separators.
+	b := &dataproto.B{Data: []byte("|"), MediaType: "text/plain"}
+	m, err := dataproto.NewMAP("app", "|", "|", "example")
+	require.NoError(t, err)
+	s, err := dataproto.Lock(b, m)
+	require.NoError(t, err)
+
+	records, err := dataproto.Decode(s)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, b, records[0])
+	require.Equal(t, m, records[1])
+	value, ok := records[1].(*dataproto.MAP).Get("|")
+	require.True(t, ok)
+	require.Equal(t, "example", value)
+}
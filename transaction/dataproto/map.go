@@ -0,0 +1,72 @@
+package dataproto
+
+// MAPSet is the MAP command which sets key value pairs.
+const MAPSet = "SET"
+
+// KeyValue is a MAP key value pair.
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// MAP is a MAP SET record, which attaches key value metadata to the
+// transaction. Keys are conventionally led by "app" and "type".
+//
+// <MAP prefix> SET <key> <value> [<key> <value> ...]
+type MAP struct {
+	Pairs []KeyValue
+}
+
+// NewMAP returns a MAP record setting the pairs, given as alternating
+// keys and values.
+func NewMAP(keyValues ...string) (*MAP, error) {
+	if len(keyValues) == 0 || len(keyValues)%2 != 0 {
+		return nil, ErrBadMAPPairs
+	}
+	m := &MAP{Pairs: make([]KeyValue, 0, len(keyValues)/2)}
+	for i := 0; i < len(keyValues); i += 2 {
+		m.Pairs = append(m.Pairs, KeyValue{Key: keyValues[i], Value: keyValues[i+1]})
+	}
+	return m, nil
+}
+
+// Get returns the value of the last pair with the key.
+func (m *MAP) Get(key string) (string, bool) {
+	for i := len(m.Pairs) - 1; i >= 0; i-- {
+		if m.Pairs[i].Key == key {
+			return m.Pairs[i].Value, true
+		}
+	}
+	return "", false
+}
+
+func (m *MAP) Prefix() string {
+	return MAPPrefix
+}
+
+func (m *MAP) Fields() ([][]byte, error) {
+	if len(m.Pairs) == 0 {
+		return nil, ErrBadMAPPairs
+	}
+	fields := make([][]byte, 0, 1+2*len(m.Pairs))
+	fields = append(fields, []byte(MAPSet))
+	for _, kv := range m.Pairs {
+		fields = append(fields, []byte(kv.Key), []byte(kv.Value))
+	}
+	return fields, nil
+}
+
+func decodeMAP(fields [][]byte) (*MAP, error) {
+	if len(fields) == 0 || string(fields[0]) != MAPSet {
+		return nil, ErrUnknownCommand
+	}
+	fields = fields[1:]
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return nil, ErrBadMAPPairs
+	}
+	m := &MAP{Pairs: make([]KeyValue, 0, len(fields)/2)}
+	for i := 0; i < len(fields); i += 2 {
+		m.Pairs = append(m.Pairs, KeyValue{Key: string(fields[i]), Value: string(fields[i+1])})
+	}
+	return m, nil
+}
@@ -12,6 +12,7 @@ var (
 	ErrUnsupportedScript = errors.New("non-P2PKH input used in the tx - unsupported")
 	ErrInvalidScriptType = errors.New("invalid script type")
 	ErrNoUnlocker        = errors.New("unlocker not supplied")
+	ErrNoUnlockingScript = errors.New("inputs must have an unlocking script or an unlocker")
 	ErrBadMerkleProof    = errors.New("bad merkle proof")
 )
 
@@ -62,7 +63,9 @@ var (
 
 // Sentinal errors reported by ordinal inscriptions.
 var (
-	ErrOutputsNotEmpty = errors.New("transaction outputs must be empty to avoid messing with Ordinal ordering scheme")
+	ErrOutputsNotEmpty   = errors.New("transaction outputs must be empty to avoid messing with Ordinal ordering scheme")
+	ErrSatoshiOutOfRange = errors.New("satoshi index is beyond the value of the input")
+	ErrSatoshiPaidAsFee  = errors.New("satoshi is paid to the miner as fee")
 )
 
 // Sentinal errors reported by PSBTs.
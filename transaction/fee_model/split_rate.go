@@ -0,0 +1,42 @@
+package feemodel
+
+import (
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// SplitRate charges the bytes of data output scripts (those for which
+// Script.IsData is true) and all other bytes of the transaction at separate
+// rates, both in satoshis per kilobyte. Inputs without an unlocking script
+// are sized using their template's EstimateLength.
+type SplitRate struct {
+	StandardSatoshis uint64
+	DataSatoshis     uint64
+}
+
+// ComputeFee rounds the size of the transaction up to a whole kilobyte, as
+// SatoshisPerKilobyte does, and charges the data bytes at the data rate and
+// the rest, including the rounding, at the standard rate. With equal rates
+// the fee is the same as that of SatoshisPerKilobyte.
+func (s *SplitRate) ComputeFee(tx *transaction.Transaction) (uint64, error) {
+	standard, data, err := splitSize(tx)
+	if err != nil {
+		return 0, err
+	}
+	rounded := (uint64(standard+data) + 999) / 1000 * 1000
+	total := (rounded-uint64(data))*s.StandardSatoshis + uint64(data)*s.DataSatoshis
+	return (total + 999) / 1000, nil
+}
+
+// splitSize returns the number of standard and data bytes in the transaction.
+func splitSize(tx *transaction.Transaction) (standard int, data int, err error) {
+	size, err := tx.EstimateSize()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, o := range tx.Outputs {
+		if o.LockingScript.IsData() {
+			data += len(*o.LockingScript)
+		}
+	}
+	return size - data, data, nil
+}
@@ -0,0 +1,102 @@
+package feemodel_test
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	feemodel "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitRate(t *testing.T) {
+	feeModel := &feemodel.SplitRate{StandardSatoshis: 100, DataSatoshis: 10}
+
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+		0,
+		"76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac",
+		100000,
+		nil,
+	))
+	tx.Inputs[0].UnlockingScript = script.NewFromBytes(make([]byte, 106))
+	require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 1000))
+	require.NoError(t, tx.AddOpReturnOutput(make([]byte, 1000)))
+
+	// The size is rounded up to 2 kilobytes, and everything but the data
+	// output script is charged at the standard rate.
+	dataLen := len(*tx.Outputs[1].LockingScript)
+	require.Equal(t, 2, (tx.Size()+999)/1000)
+	expected := (uint64(2000-dataLen)*100 + uint64(dataLen)*10 + 999) / 1000
+
+	fee, err := feeModel.ComputeFee(tx)
+	require.NoError(t, err)
+	require.Equal(t, expected, fee)
+
+	flat, err := (&feemodel.SatoshisPerKilobyte{Satoshis: 100}).ComputeFee(tx)
+	require.NoError(t, err)
+	require.Less(t, fee, flat)
+
+	// With equal rates the split makes no difference.
+	fee, err = (&feemodel.SplitRate{StandardSatoshis: 100, DataSatoshis: 100}).ComputeFee(tx)
+	require.NoError(t, err)
+	require.Equal(t, flat, fee)
+}
+
+func TestSplitRateEqualsSatoshisPerKilobyte(t *testing.T) {
+	for _, rate := range []uint64{1, 50, 100, 500} {
+		for _, dataSize := range []int{0, 10, 700, 993, 5000} {
+			tx := transaction.NewTransaction()
+			require.NoError(t, tx.AddInputFrom(
+				"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+				0,
+				"76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac",
+				100000,
+				nil,
+			))
+			tx.Inputs[0].UnlockingScript = script.NewFromBytes(make([]byte, 107))
+			require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 1000))
+			require.NoError(t, tx.AddOpReturnOutput(make([]byte, dataSize)))
+
+			split, err := (&feemodel.SplitRate{StandardSatoshis: rate, DataSatoshis: rate}).ComputeFee(tx)
+			require.NoError(t, err)
+			flat, err := (&feemodel.SatoshisPerKilobyte{Satoshis: rate}).ComputeFee(tx)
+			require.NoError(t, err)
+			require.Equal(t, flat, split, "rate %d, data %d", rate, dataSize)
+		}
+	}
+}
+
+func TestSplitRateUnsignedInputs(t *testing.T) {
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	unlocker, err := p2pkh.Unlock(key, nil)
+	require.NoError(t, err)
+
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+		0,
+		"76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac",
+		100000,
+		unlocker,
+	))
+	require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 1000))
+	require.NoError(t, tx.AddOpReturnOutput(make([]byte, 850)))
+	require.Nil(t, tx.Inputs[0].UnlockingScript)
+
+	// The unlocking script is sized from the template's 107 byte estimate,
+	// which takes the transaction over a kilobyte.
+	require.Less(t, tx.Size(), 1000)
+	require.Equal(t, uint32(107), unlocker.EstimateLength(tx, 0))
+	dataLen := len(*tx.Outputs[1].LockingScript)
+	require.Greater(t, tx.Size()+107, 1000)
+	expected := (uint64(2000-dataLen)*100 + uint64(dataLen)*10 + 999) / 1000
+
+	fee, err := (&feemodel.SplitRate{StandardSatoshis: 100, DataSatoshis: 10}).ComputeFee(tx)
+	require.NoError(t, err)
+	require.Equal(t, expected, fee)
+}
@@ -1,9 +1,8 @@
 package transaction
 
 import (
+	"math/rand/v2"
 	"slices"
-
-	"github.com/pkg/errors"
 )
 
 type ChangeDistribution int
@@ -13,12 +12,52 @@ var (
 	ChangeDistributionRandom ChangeDistribution = 2
 )
 
+// DefaultChangeDustFloor is the smallest amount a change output will be
+// assigned when change is distributed randomly.
+const DefaultChangeDustFloor uint64 = 1
+
 type FeeModel interface {
 	ComputeFee(tx *Transaction) (uint64, error)
 }
 
-// Fee computes the fee for the transaction.
-func (tx *Transaction) Fee(f FeeModel, changeDistribution ChangeDistribution) error {
+// FeeOptionFunc for setting options used when computing the fee.
+type FeeOptionFunc func(o *feeOpts)
+
+type feeOpts struct {
+	rand      *rand.Rand
+	dustFloor uint64
+}
+
+// WithChangeRand configures the source of randomness used by
+// ChangeDistributionRandom. Supplying a seeded source makes the
+// distribution deterministic.
+func WithChangeRand(r *rand.Rand) FeeOptionFunc {
+	return func(o *feeOpts) {
+		o.rand = r
+	}
+}
+
+// WithChangeDustFloor configures the minimum amount of satoshis that
+// ChangeDistributionRandom will assign to a change output. Change outputs
+// which cannot be funded up to the floor are removed.
+func WithChangeDustFloor(sats uint64) FeeOptionFunc {
+	return func(o *feeOpts) {
+		o.dustFloor = sats
+	}
+}
+
+// Fee computes the fee for the transaction. Inputs do not need to be signed
+// first, as fee models size them using EstimateSize, so the transaction can
+// be signed once the change has been set.
+func (tx *Transaction) Fee(f FeeModel, changeDistribution ChangeDistribution, opts ...FeeOptionFunc) error {
+	fo := &feeOpts{dustFloor: DefaultChangeDustFloor}
+	for _, opt := range opts {
+		opt(fo)
+	}
+	if fo.dustFloor == 0 {
+		fo.dustFloor = DefaultChangeDustFloor
+	}
+
 	fee, err := f.ComputeFee(tx)
 	if err != nil {
 		return err
@@ -44,6 +83,9 @@ func (tx *Transaction) Fee(f FeeModel, changeDistribution ChangeDistribution) er
 		return ErrInsufficientInputs
 	}
 	change := satsIn - satsOut - fee
+	if changeOuts == 0 {
+		return nil
+	}
 	// There is not enough change to distribute among the change outputs.
 	// We'll remove all change outputs and leave the extra for the miners.
 	if changeOuts > change {
@@ -53,7 +95,7 @@ func (tx *Transaction) Fee(f FeeModel, changeDistribution ChangeDistribution) er
 	} else {
 		switch changeDistribution {
 		case ChangeDistributionRandom:
-			return errors.New("not-implemented")
+			tx.distributeChangeRandom(change, fo)
 		case ChangeDistributionEqual:
 			changePerOutput := change / changeOuts
 			for _, o := range tx.Outputs {
@@ -73,3 +115,49 @@ func (tx *Transaction) GetFee() (total uint64, err error) {
 		return totalIn - tx.TotalOutputSatoshis(), nil
 	}
 }
+
+// distributeChangeRandom splits change across the change outputs in random,
+// non-uniform amounts so that change outputs cannot be identified by their
+// value. Every output receives at least the dust floor; if there is not
+// enough change for that, surplus change outputs are removed.
+func (tx *Transaction) distributeChangeRandom(change uint64, o *feeOpts) {
+	changeOuts := make([]*TransactionOutput, 0)
+	for _, out := range tx.Outputs {
+		if out.Change {
+			changeOuts = append(changeOuts, out)
+		}
+	}
+
+	keep := uint64(len(changeOuts))
+	if maxOuts := change / o.dustFloor; keep > maxOuts {
+		keep = maxOuts
+	}
+	if keep < uint64(len(changeOuts)) {
+		surplus := changeOuts[keep:]
+		tx.Outputs = slices.DeleteFunc(tx.Outputs, func(out *TransactionOutput) bool {
+			return slices.Contains(surplus, out)
+		})
+		changeOuts = changeOuts[:keep]
+	}
+	if keep == 0 {
+		return
+	}
+
+	uint64N := rand.Uint64N
+	if o.rand != nil {
+		uint64N = o.rand.Uint64N
+	}
+
+	// Pick random cut points across the amount above the floor and assign
+	// the gaps between them to the outputs.
+	spare := change - keep*o.dustFloor
+	cuts := make([]uint64, 0, keep+1)
+	cuts = append(cuts, 0, spare)
+	for i := uint64(1); i < keep; i++ {
+		cuts = append(cuts, uint64N(spare+1))
+	}
+	slices.Sort(cuts)
+	for i, out := range changeOuts {
+		out.Satoshis = o.dustFloor + cuts[i+1] - cuts[i]
+	}
+}
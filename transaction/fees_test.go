@@ -0,0 +1,88 @@
+package transaction_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	feemodel "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
+	"github.com/stretchr/testify/require"
+)
+
+func newChangeTx(t *testing.T, satoshis uint64, changeOuts int) *transaction.Transaction {
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+		0,
+		"76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac",
+		satoshis,
+		nil,
+	))
+	tx.Inputs[0].UnlockingScript = script.NewFromBytes(make([]byte, 106))
+	require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 1000))
+	for i := 0; i < changeOuts; i++ {
+		require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 0))
+		tx.Outputs[len(tx.Outputs)-1].Change = true
+	}
+	return tx
+}
+
+func TestFeeChangeDistributionRandom(t *testing.T) {
+	feeModel := &feemodel.SatoshisPerKilobyte{Satoshis: 1}
+
+	t.Run("deterministic with seeded source", func(t *testing.T) {
+		var amounts [2][]uint64
+		for run := range amounts {
+			tx := newChangeTx(t, 100000, 4)
+			err := tx.Fee(feeModel, transaction.ChangeDistributionRandom,
+				transaction.WithChangeRand(rand.New(rand.NewPCG(1, 2))))
+			require.NoError(t, err)
+
+			for _, o := range tx.Outputs {
+				if o.Change {
+					amounts[run] = append(amounts[run], o.Satoshis)
+				}
+			}
+
+			fee, err := tx.GetFee()
+			require.NoError(t, err)
+			expectedFee, err := feeModel.ComputeFee(tx)
+			require.NoError(t, err)
+			require.Equal(t, expectedFee, fee)
+		}
+		require.Equal(t, amounts[0], amounts[1])
+		require.Len(t, amounts[0], 4)
+		require.NotEqual(t, amounts[0][0], amounts[0][1])
+	})
+
+	t.Run("respects dust floor", func(t *testing.T) {
+		tx := newChangeTx(t, 2000, 4)
+		err := tx.Fee(feeModel, transaction.ChangeDistributionRandom,
+			transaction.WithChangeRand(rand.New(rand.NewPCG(3, 4))),
+			transaction.WithChangeDustFloor(300))
+		require.NoError(t, err)
+
+		changeOuts := 0
+		for _, o := range tx.Outputs {
+			if o.Change {
+				changeOuts++
+				require.GreaterOrEqual(t, o.Satoshis, uint64(300))
+			}
+		}
+		// 2000 in, 1000 paid, 1 fee: 999 change only funds three outputs.
+		require.Equal(t, 3, changeOuts)
+
+		fee, err := tx.GetFee()
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), fee)
+	})
+
+	t.Run("change below floor goes to miners", func(t *testing.T) {
+		tx := newChangeTx(t, 1100, 2)
+		err := tx.Fee(feeModel, transaction.ChangeDistributionRandom,
+			transaction.WithChangeDustFloor(500))
+		require.NoError(t, err)
+		require.Len(t, tx.Outputs, 1)
+	})
+}
@@ -48,8 +48,8 @@ func (tx *Transaction) Inscribe(ia *script.InscriptionArgs) error {
 
 	if ia.EnrichedArgs != nil {
 		if len(ia.EnrichedArgs.OpReturnData) > 0 {
-			_ = ins.AppendOpcodes(script.OpRETURN)
-			if err := ins.AppendPushDataArray(ia.EnrichedArgs.OpReturnData); err != nil {
+			_ = s.AppendOpcodes(script.OpRETURN)
+			if err := s.AppendPushDataArray(ia.EnrichedArgs.OpReturnData); err != nil {
 				return err
 			}
 		}
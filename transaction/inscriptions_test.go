@@ -0,0 +1,94 @@
+package transaction_test
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/require"
+)
+
+func newP2PKHLock(t *testing.T) *script.Script {
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(key.PubKey(), true)
+	require.NoError(t, err)
+	s, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	return s
+}
+
+func TestInscribeParseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	lock := newP2PKHLock(t)
+	ia := &script.InscriptionArgs{
+		LockingScript: lock,
+		Data:          []byte("Hello, world!"),
+		ContentType:   "text/plain;charset=utf-8",
+		EnrichedArgs: &script.EnrichedInscriptionArgs{
+			OpReturnData: [][]byte{[]byte("1PuQa7K62MiKCtssSLKy1kh56WWU7MtUR5"), []byte("SET"), []byte("app")},
+		},
+	}
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.Inscribe(ia))
+
+	parsed, err := script.ParseInscription(tx.Outputs[0].LockingScript)
+	require.NoError(t, err)
+	require.Equal(t, ia.ContentType, parsed.ContentType)
+	require.Equal(t, ia.Data, parsed.Data)
+	require.Equal(t, lock.Bytes(), parsed.LockingScript.Bytes())
+	require.NotNil(t, parsed.EnrichedArgs)
+	require.Equal(t, ia.EnrichedArgs.OpReturnData, parsed.EnrichedArgs.OpReturnData)
+}
+
+func TestParseInscription(t *testing.T) {
+	t.Parallel()
+
+	lock := newP2PKHLock(t)
+
+	t.Run("envelope after lock with extra tags and split body", func(t *testing.T) {
+		s := script.NewFromBytes(lock.Bytes())
+		_ = s.AppendOpcodes(script.OpFALSE, script.OpIF)
+		require.NoError(t, s.AppendPushDataString("ord"))
+		_ = s.AppendOpcodes(script.Op1)
+		require.NoError(t, s.AppendPushDataString("image/png"))
+		_ = s.AppendOpcodes(script.Op3)
+		require.NoError(t, s.AppendPushData(make([]byte, 36)))
+		// Metadata tag pushed as a single byte rather than OP_5.
+		require.NoError(t, s.AppendPushData([]byte{5}))
+		require.NoError(t, s.AppendPushDataString("{}"))
+		_ = s.AppendOpcodes(script.Op0)
+		require.NoError(t, s.AppendPushData(make([]byte, 600)))
+		require.NoError(t, s.AppendPushData([]byte{1, 2, 3}))
+		_ = s.AppendOpcodes(script.OpENDIF)
+
+		parsed, err := script.ParseInscription(s)
+		require.NoError(t, err)
+		require.Equal(t, "image/png", parsed.ContentType)
+		require.Len(t, parsed.Data, 603)
+		require.Equal(t, []byte{1, 2, 3}, parsed.Data[600:])
+		require.Equal(t, lock.Bytes(), parsed.LockingScript.Bytes())
+		require.Nil(t, parsed.EnrichedArgs)
+	})
+
+	t.Run("not an inscription", func(t *testing.T) {
+		_, err := script.ParseInscription(lock)
+		require.ErrorIs(t, err, script.ErrInscriptionNotFound)
+	})
+
+	t.Run("unterminated envelope", func(t *testing.T) {
+		s := &script.Script{}
+		_ = s.AppendOpcodes(script.OpFALSE, script.OpIF)
+		require.NoError(t, s.AppendPushDataString("ord"))
+		_ = s.AppendOpcodes(script.Op1)
+		require.NoError(t, s.AppendPushDataString("text/plain"))
+		_ = s.AppendOpcodes(script.Op0)
+		require.NoError(t, s.AppendPushDataString("body"))
+
+		_, err := script.ParseInscription(s)
+		require.ErrorIs(t, err, script.ErrInvalidInscription)
+	})
+}
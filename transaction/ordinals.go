@@ -0,0 +1,140 @@
+package transaction
+
+import (
+	script "github.com/bsv-blockchain/go-sdk/script"
+)
+
+// SatoshiRange is a contiguous run of satoshis moving from an input to an
+// output of a transaction.
+type SatoshiRange struct {
+	// Input is the index of the input the satoshis come from and
+	// InputOffset the position of the first of them within that input.
+	Input       uint32
+	InputOffset uint64
+	// Output is the index of the output receiving the satoshis, or -1 for
+	// satoshis paid to the miner as fee, and OutputOffset the position of
+	// the first of them within that output.
+	Output       int
+	OutputOffset uint64
+	Satoshis     uint64
+}
+
+// SatoshiRanges maps the satoshis of every input to the outputs which
+// receive them, first in first out as described by the Ordinals Theory
+// Handbook (https://docs.ordinals.com/overview.html): satoshis are
+// numbered through the inputs in order and assigned to the outputs in
+// order, with any left over going to the fee.
+//
+// The source satoshis of every input must be known.
+func (tx *Transaction) SatoshiRanges() ([]SatoshiRange, error) {
+	ranges := make([]SatoshiRange, 0, len(tx.Inputs)+len(tx.Outputs))
+	out, outOffset := 0, uint64(0)
+	for i, in := range tx.Inputs {
+		prevSats := in.SourceTxSatoshis()
+		if prevSats == nil || *prevSats == 0 {
+			return nil, ErrInputSatsZero
+		}
+		for inOffset := uint64(0); inOffset < *prevSats; {
+			// Skip outputs which have been filled, including empty ones.
+			for out < len(tx.Outputs) && outOffset == tx.Outputs[out].Satoshis {
+				out, outOffset = out+1, 0
+			}
+			r := SatoshiRange{
+				Input:        uint32(i),
+				InputOffset:  inOffset,
+				Output:       -1,
+				OutputOffset: 0,
+				Satoshis:     *prevSats - inOffset,
+			}
+			if out < len(tx.Outputs) {
+				r.Output, r.OutputOffset = out, outOffset
+				r.Satoshis = min(r.Satoshis, tx.Outputs[out].Satoshis-outOffset)
+				outOffset += r.Satoshis
+			}
+			ranges = append(ranges, r)
+			inOffset += r.Satoshis
+		}
+	}
+	for out < len(tx.Outputs) && outOffset == tx.Outputs[out].Satoshis {
+		out, outOffset = out+1, 0
+	}
+	if out < len(tx.Outputs) {
+		return nil, ErrInsufficientInputs
+	}
+	return ranges, nil
+}
+
+// TraceSatoshi returns the output which receives satoshi satIdx of the
+// input at inputIdx, and the satoshi's position within that output.
+// ErrSatoshiPaidAsFee is returned if the satoshi goes to the miner.
+func (tx *Transaction) TraceSatoshi(inputIdx uint32, satIdx uint64) (uint32, uint64, error) {
+	if int(inputIdx) >= len(tx.Inputs) {
+		return 0, 0, ErrInputNoExist
+	}
+	if prevSats := tx.Inputs[inputIdx].SourceTxSatoshis(); prevSats != nil && satIdx >= *prevSats {
+		return 0, 0, ErrSatoshiOutOfRange
+	}
+	ranges, err := tx.SatoshiRanges()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, r := range ranges {
+		if r.Input != inputIdx || satIdx < r.InputOffset || satIdx >= r.InputOffset+r.Satoshis {
+			continue
+		}
+		if r.Output < 0 {
+			return 0, 0, ErrSatoshiPaidAsFee
+		}
+		return uint32(r.Output), r.OutputOffset + satIdx - r.InputOffset, nil
+	}
+	return 0, 0, ErrSatoshiOutOfRange
+}
+
+// TransferOrdinal builds the transaction to move satoshi satIdx of the
+// ordinal input, such as an inscription, to a new 1 satoshi output locked
+// with to. The fee is paid by the funding inputs, which are added after the
+// ordinal input so they can never take its place.
+//
+// Any satoshis of the ordinal input before satIdx are returned to change in
+// a separate output ahead of the transfer. Everything else, less the fee
+// computed with f, is returned to change in the last output.
+func (tx *Transaction) TransferOrdinal(ordinal *TransactionInput, satIdx uint64, to *script.Script,
+	funding []*TransactionInput, change *script.Script, f FeeModel) error {
+	if tx.InputCount() > 0 || tx.OutputCount() > 0 {
+		return ErrOutputsNotEmpty
+	}
+	if ordinal == nil || to == nil || change == nil || len(funding) == 0 {
+		return ErrEmptyValues
+	}
+	prevSats := ordinal.SourceTxSatoshis()
+	if prevSats == nil || *prevSats == 0 {
+		return ErrInputSatsZero
+	}
+	if satIdx >= *prevSats {
+		return ErrSatoshiOutOfRange
+	}
+
+	tx.AddInput(ordinal)
+	for _, in := range funding {
+		tx.AddInput(in)
+	}
+	if satIdx > 0 {
+		tx.AddOutput(&TransactionOutput{Satoshis: satIdx, LockingScript: change})
+	}
+	tx.AddOutput(&TransactionOutput{Satoshis: 1, LockingScript: to})
+	tx.AddOutput(&TransactionOutput{LockingScript: change, Change: true})
+
+	if err := tx.Fee(f, ChangeDistributionEqual); err != nil {
+		return err
+	}
+
+	// The fee must not have eaten into the ordinal input.
+	out, offset, err := tx.TraceSatoshi(0, satIdx)
+	if err != nil {
+		return err
+	}
+	if offset != 0 || tx.Outputs[out].LockingScript != to {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package transaction_test
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	feemodel "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/require"
+)
+
+func newSourcedInput(t *testing.T, vout uint32, sats uint64, lock *script.Script,
+	unlocker transaction.UnlockingScriptTemplate) *transaction.TransactionInput {
+	txid := chainhash.DoubleHashH([]byte("source"))
+	in := &transaction.TransactionInput{
+		SourceTXID:              &txid,
+		SourceTxOutIndex:        vout,
+		SequenceNumber:          transaction.DefaultSequenceNumber,
+		UnlockingScriptTemplate: unlocker,
+	}
+	in.SetSourceTxOutput(&transaction.TransactionOutput{Satoshis: sats, LockingScript: lock})
+	return in
+}
+
+func TestSatoshiRanges(t *testing.T) {
+	t.Parallel()
+
+	// [a b] [c] [d e f] → [a b c d] [e]  with f paid as fee
+	lock := newP2PKHLock(t)
+	tx := transaction.NewTransaction()
+	tx.AddInput(newSourcedInput(t, 0, 2, lock, nil))
+	tx.AddInput(newSourcedInput(t, 1, 1, lock, nil))
+	tx.AddInput(newSourcedInput(t, 2, 3, lock, nil))
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: 4, LockingScript: lock})
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: 1, LockingScript: lock})
+
+	ranges, err := tx.SatoshiRanges()
+	require.NoError(t, err)
+	require.Equal(t, []transaction.SatoshiRange{
+		{Input: 0, InputOffset: 0, Output: 0, OutputOffset: 0, Satoshis: 2},
+		{Input: 1, InputOffset: 0, Output: 0, OutputOffset: 2, Satoshis: 1},
+		{Input: 2, InputOffset: 0, Output: 0, OutputOffset: 3, Satoshis: 1},
+		{Input: 2, InputOffset: 1, Output: 1, OutputOffset: 0, Satoshis: 1},
+		{Input: 2, InputOffset: 2, Output: -1, OutputOffset: 0, Satoshis: 1},
+	}, ranges)
+
+	out, offset, err := tx.TraceSatoshi(2, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), out)
+	require.Zero(t, offset)
+
+	out, offset, err = tx.TraceSatoshi(1, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), out)
+	require.Equal(t, uint64(2), offset)
+
+	_, _, err = tx.TraceSatoshi(2, 2)
+	require.ErrorIs(t, err, transaction.ErrSatoshiPaidAsFee)
+	_, _, err = tx.TraceSatoshi(0, 2)
+	require.ErrorIs(t, err, transaction.ErrSatoshiOutOfRange)
+
+	tx.Outputs[1].Satoshis = 3
+	_, err = tx.SatoshiRanges()
+	require.ErrorIs(t, err, transaction.ErrInsufficientInputs)
+}
+
+func TestTransferOrdinal(t *testing.T) {
+	t.Parallel()
+
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(key.PubKey(), true)
+	require.NoError(t, err)
+	lock, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	unlocker, err := p2pkh.Unlock(key, nil)
+	require.NoError(t, err)
+	to := newP2PKHLock(t)
+
+	// The inscribed satoshi is the third of a 5 satoshi output.
+	ordinal := newSourcedInput(t, 0, 5, lock, unlocker)
+	funding := []*transaction.TransactionInput{newSourcedInput(t, 1, 1000, lock, unlocker)}
+
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.TransferOrdinal(ordinal, 2, to, funding, lock, &feemodel.SatoshisPerKilobyte{Satoshis: 50}))
+	require.NoError(t, tx.Sign())
+
+	require.Len(t, tx.Outputs, 3)
+	require.Equal(t, uint64(2), tx.Outputs[0].Satoshis)
+	require.Equal(t, uint64(1), tx.Outputs[1].Satoshis)
+	require.Equal(t, to, tx.Outputs[1].LockingScript)
+	require.Equal(t, uint64(1005-3-50), tx.Outputs[2].Satoshis)
+
+	out, offset, err := tx.TraceSatoshi(0, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), out)
+	require.Zero(t, offset)
+
+	require.ErrorIs(t, transaction.NewTransaction().TransferOrdinal(ordinal, 5, to, funding, lock,
+		&feemodel.SatoshisPerKilobyte{Satoshis: 50}), transaction.ErrSatoshiOutOfRange)
+	require.ErrorIs(t, tx.TransferOrdinal(ordinal, 0, to, funding, lock,
+		&feemodel.SatoshisPerKilobyte{Satoshis: 50}), transaction.ErrOutputsNotEmpty)
+}
@@ -0,0 +1,250 @@
+// Package policy checks transactions against configurable miner relay
+// policy, so that non-standard transactions can be caught before they are
+// broadcast rather than through an opaque rejection from the broadcaster.
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+const (
+	// DefaultMaxTxSize is the default maximum size of a transaction in bytes.
+	DefaultMaxTxSize = 10_000_000
+	// DefaultDustLimit is the default minimum value of a spendable output.
+	DefaultDustLimit = 1
+	// MaxMoney is the largest value any output can carry, in satoshis.
+	MaxMoney = 21_000_000 * 100_000_000
+	// multiSigOps is the number of signature operations counted for a
+	// CHECKMULTISIG whose key count cannot be determined.
+	multiSigOps = 20
+)
+
+// Rule identifies the policy rule a transaction violates.
+type Rule string
+
+const (
+	RuleTxSize          Rule = "tx-size"
+	RuleNoInputs        Rule = "no-inputs"
+	RuleNoOutputs       Rule = "no-outputs"
+	RuleDuplicateIn     Rule = "duplicate-input"
+	RulePushOnly        Rule = "unlocking-script-not-push-only"
+	RuleSigOps          Rule = "sigops"
+	RuleDust            Rule = "dust"
+	RuleZeroValue       Rule = "zero-value"
+	RuleOutputValue     Rule = "output-value"
+	RuleDataCarrier     Rule = "data-carrier"
+	RuleInsufficientFee Rule = "insufficient-fee"
+)
+
+// Violation describes a single policy violation. Input and Output hold the
+// index of the offending input or output, or -1 when the rule applies to the
+// transaction as a whole.
+type Violation struct {
+	Rule    Rule
+	Input   int
+	Output  int
+	Message string
+}
+
+func (v Violation) String() string {
+	switch {
+	case v.Input >= 0:
+		return fmt.Sprintf("%s: input %d: %s", v.Rule, v.Input, v.Message)
+	case v.Output >= 0:
+		return fmt.Sprintf("%s: output %d: %s", v.Rule, v.Output, v.Message)
+	}
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// Violations is the list of policy violations found in a transaction.
+type Violations []Violation
+
+// Error joins the violations so that a non-empty list can be returned as an error.
+func (v Violations) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, violation := range v {
+		msgs = append(msgs, violation.String())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Has reports whether any violation of the rule was found.
+func (v Violations) Has(rule Rule) bool {
+	for _, violation := range v {
+		if violation.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy is a set of miner relay rules. Limits which are zero are not checked.
+type Policy struct {
+	// MaxTxSize is the maximum size of the transaction in bytes.
+	MaxTxSize int
+	// DustLimit is the minimum value of any output which is not an OP_RETURN data output.
+	DustLimit uint64
+	// MaxSigOps is the maximum number of signature operations in the
+	// transaction's locking and unlocking scripts.
+	MaxSigOps int
+	// MaxDataCarrierSize is the maximum total size of the
+	// transaction's OP_RETURN data output scripts.
+	MaxDataCarrierSize int
+	// FeeModel, if set, is used to check the transaction pays a sufficient fee.
+	// This requires the source output of every input to be known.
+	FeeModel transaction.FeeModel
+}
+
+// DefaultPolicy returns a policy with the defaults applied by BSV nodes.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		MaxTxSize: DefaultMaxTxSize,
+		DustLimit: DefaultDustLimit,
+	}
+}
+
+// Check checks the transaction against the default policy.
+func Check(tx *transaction.Transaction) Violations {
+	return DefaultPolicy().Check(tx)
+}
+
+// Check returns every violation of the policy found in the transaction,
+// or nil if it is standard.
+func (p *Policy) Check(tx *transaction.Transaction) Violations {
+	var v Violations
+	add := func(rule Rule, input, output int, format string, args ...any) {
+		v = append(v, Violation{Rule: rule, Input: input, Output: output, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(tx.Inputs) == 0 {
+		add(RuleNoInputs, -1, -1, "transaction has no inputs")
+	}
+	if len(tx.Outputs) == 0 {
+		add(RuleNoOutputs, -1, -1, "transaction has no outputs")
+	}
+	if size := tx.Size(); p.MaxTxSize > 0 && size > p.MaxTxSize {
+		add(RuleTxSize, -1, -1, "size %d exceeds %d bytes", size, p.MaxTxSize)
+	}
+
+	sigOps := 0
+	seen := make(map[string]int, len(tx.Inputs))
+	for i, in := range tx.Inputs {
+		if in.SourceTXID != nil {
+			outpoint := fmt.Sprintf("%s:%d", in.SourceTXID, in.SourceTxOutIndex)
+			if first, ok := seen[outpoint]; ok {
+				add(RuleDuplicateIn, i, -1, "spends %s already spent by input %d", outpoint, first)
+			} else {
+				seen[outpoint] = i
+			}
+		}
+		if in.UnlockingScript != nil {
+			if !isPushOnly(in.UnlockingScript) {
+				add(RulePushOnly, i, -1, "unlocking script contains non-push opcodes")
+			}
+			sigOps += countSigOps(in.UnlockingScript)
+		}
+	}
+
+	dataSize := 0
+	for i, out := range tx.Outputs {
+		isData := out.LockingScript != nil && out.LockingScript.IsData()
+		switch {
+		case out.Satoshis > MaxMoney:
+			// Values above MaxMoney include those which are negative when read as a signed amount.
+			add(RuleOutputValue, -1, i, "value %d exceeds the maximum of %d", out.Satoshis, uint64(MaxMoney))
+		case out.Satoshis == 0 && !isData:
+			add(RuleZeroValue, -1, i, "spendable output has no value")
+		case out.Satoshis < p.DustLimit && !isData:
+			add(RuleDust, -1, i, "value %d is below the dust limit of %d", out.Satoshis, p.DustLimit)
+		}
+		if out.LockingScript == nil {
+			continue
+		}
+		if isData {
+			dataSize += len(*out.LockingScript)
+		} else {
+			sigOps += countSigOps(out.LockingScript)
+		}
+	}
+	var total uint64
+	for _, out := range tx.Outputs {
+		// Guard against overflow so that the total cannot wrap below MaxMoney.
+		if total += out.Satoshis; total > MaxMoney || total < out.Satoshis {
+			add(RuleOutputValue, -1, -1, "total output value exceeds the maximum of %d", uint64(MaxMoney))
+			break
+		}
+	}
+
+	if p.MaxSigOps > 0 && sigOps > p.MaxSigOps {
+		add(RuleSigOps, -1, -1, "%d signature operations exceeds %d", sigOps, p.MaxSigOps)
+	}
+	if p.MaxDataCarrierSize > 0 && dataSize > p.MaxDataCarrierSize {
+		add(RuleDataCarrier, -1, -1, "%d bytes of data outputs exceeds %d", dataSize, p.MaxDataCarrierSize)
+	}
+
+	if p.FeeModel != nil {
+		p.checkFee(tx, add)
+	}
+
+	return v
+}
+
+func (p *Policy) checkFee(tx *transaction.Transaction, add func(Rule, int, int, string, ...any)) {
+	required, err := p.FeeModel.ComputeFee(tx)
+	if err != nil {
+		add(RuleInsufficientFee, -1, -1, "unable to compute required fee: %s", err)
+		return
+	}
+	totalIn, err := tx.TotalInputSatoshis()
+	if err != nil {
+		add(RuleInsufficientFee, -1, -1, "unable to determine fee paid: %s", err)
+		return
+	}
+	totalOut := tx.TotalOutputSatoshis()
+	if totalIn < totalOut {
+		add(RuleInsufficientFee, -1, -1, "outputs total %d but inputs only %d", totalOut, totalIn)
+	} else if paid := totalIn - totalOut; paid < required {
+		add(RuleInsufficientFee, -1, -1, "fee %d is below the required %d", paid, required)
+	}
+}
+
+// isPushOnly reports whether the script only pushes data onto the stack.
+func isPushOnly(s *script.Script) bool {
+	chunks, err := s.Chunks()
+	if err != nil {
+		return false
+	}
+	for _, chunk := range chunks {
+		if chunk.Op > script.Op16 {
+			return false
+		}
+	}
+	return true
+}
+
+// countSigOps counts the signature operations in the script, using the key
+// count for a CHECKMULTISIG when it is preceded by a small integer.
+func countSigOps(s *script.Script) int {
+	chunks, err := s.Chunks()
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for i, chunk := range chunks {
+		switch chunk.Op {
+		case script.OpCHECKSIG, script.OpCHECKSIGVERIFY:
+			n++
+		case script.OpCHECKMULTISIG, script.OpCHECKMULTISIGVERIFY:
+			if i > 0 && chunks[i-1].Op >= script.Op1 && chunks[i-1].Op <= script.Op16 {
+				n += int(chunks[i-1].Op-script.Op1) + 1
+			} else {
+				n += multiSigOps
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,110 @@
+package policy_test
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	feemodel "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
+	"github.com/bsv-blockchain/go-sdk/transaction/policy"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/multisig"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/require"
+)
+
+const sourceTxID = "45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d"
+
+func newSignedTx(t *testing.T, satoshis, pay uint64) *transaction.Transaction {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+	lockingScript, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	unlocker, err := p2pkh.Unlock(priv, nil)
+	require.NoError(t, err)
+
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(sourceTxID, 0, lockingScript.String(), satoshis, unlocker))
+	require.NoError(t, tx.PayToAddress(address.AddressString, pay))
+	require.NoError(t, tx.Sign())
+	return tx
+}
+
+func TestCheckStandard(t *testing.T) {
+	tx := newSignedTx(t, 10000, 9900)
+	require.NoError(t, tx.AddOpReturnOutput([]byte("hello")))
+
+	p := policy.DefaultPolicy()
+	p.FeeModel = &feemodel.SatoshisPerKilobyte{Satoshis: 50}
+	require.Empty(t, p.Check(tx))
+	require.Empty(t, policy.Check(tx))
+}
+
+func TestCheckViolations(t *testing.T) {
+	tx := newSignedTx(t, 10000, 9999)
+
+	// A duplicate input with a non-push unlocking script.
+	require.NoError(t, tx.AddInputFrom(sourceTxID, 0, tx.Inputs[0].SourceTxScript().String(), 10000, nil))
+	tx.Inputs[1].UnlockingScript = script.NewFromBytes([]byte{script.OpTRUE, script.OpDUP})
+
+	pubKeys := make([]*ec.PublicKey, 3)
+	for i := range pubKeys {
+		priv, err := ec.NewPrivateKey()
+		require.NoError(t, err)
+		pubKeys[i] = priv.PubKey()
+	}
+	msLock, err := multisig.Lock(pubKeys, 2)
+	require.NoError(t, err)
+	tx.AddOutput(&transaction.TransactionOutput{LockingScript: msLock, Satoshis: 0})
+	tx.AddOutput(&transaction.TransactionOutput{LockingScript: msLock, Satoshis: 100})
+	tx.AddOutput(&transaction.TransactionOutput{LockingScript: msLock, Satoshis: policy.MaxMoney + 1})
+	require.NoError(t, tx.AddOpReturnOutput(make([]byte, 200)))
+
+	p := &policy.Policy{
+		MaxTxSize:          100,
+		DustLimit:          200,
+		MaxSigOps:          5,
+		MaxDataCarrierSize: 100,
+		FeeModel:           &feemodel.SatoshisPerKilobyte{Satoshis: 50},
+	}
+	v := p.Check(tx)
+
+	for _, rule := range []policy.Rule{
+		policy.RuleTxSize,
+		policy.RuleDuplicateIn,
+		policy.RulePushOnly,
+		policy.RuleSigOps,
+		policy.RuleDust,
+		policy.RuleZeroValue,
+		policy.RuleOutputValue,
+		policy.RuleDataCarrier,
+		policy.RuleInsufficientFee,
+	} {
+		require.True(t, v.Has(rule), rule)
+	}
+	require.False(t, v.Has(policy.RuleNoInputs))
+
+	for _, violation := range v {
+		switch violation.Rule {
+		case policy.RuleDuplicateIn, policy.RulePushOnly:
+			require.Equal(t, 1, violation.Input)
+		case policy.RuleZeroValue:
+			require.Equal(t, 1, violation.Output)
+		case policy.RuleDust:
+			require.Equal(t, 2, violation.Output)
+		}
+	}
+	require.Contains(t, v.Error(), "duplicate-input: input 1")
+}
+
+func TestCheckFee(t *testing.T) {
+	p := &policy.Policy{FeeModel: &feemodel.SatoshisPerKilobyte{Satoshis: 50}}
+
+	v := p.Check(newSignedTx(t, 10000, 9999))
+	require.Len(t, v, 1)
+	require.Equal(t, policy.RuleInsufficientFee, v[0].Rule)
+
+	require.Empty(t, p.Check(newSignedTx(t, 10000, 9950)))
+}
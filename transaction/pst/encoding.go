@@ -0,0 +1,242 @@
+package pst
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"sort"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+// magic prefixes a serialised PST, followed by the format version.
+var magic = []byte("pst")
+
+const formatVersion byte = 1
+
+/*
+Field              Description                                          Size
+----------------------------------------------------------------------------------------
+magic              "pst"                                                3 bytes
+version            format version, currently 1                          1 byte
+tx                 VarInt length followed by the transaction in EF      <length>
+input count        VarInt                                               1-9 bytes
+inputs             for each input:
+  sighash flag                                                          1 byte
+  threshold        VarInt                                               1-9 bytes
+  pubkeys          VarInt count, then compressed public keys            33 bytes each
+  signatures       VarInt count, then public key, VarInt length, sig
+  derivations      VarInt count, then public key, VarInt length, hint
+*/
+
+// Bytes serialises the PST. The transaction is written in EF, so the
+// source output of every input is carried along with it.
+func (p *PST) Bytes() ([]byte, error) {
+	ef, err := p.Tx.EF()
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Inputs) != len(p.Tx.Inputs) {
+		return nil, ErrInputCountMismatch
+	}
+
+	b := make([]byte, 0, len(ef)+len(p.Inputs)*128)
+	b = append(b, magic...)
+	b = append(b, formatVersion)
+	b = append(b, transaction.VarInt(uint64(len(ef))).Bytes()...)
+	b = append(b, ef...)
+	b = append(b, transaction.VarInt(uint64(len(p.Inputs))).Bytes()...)
+
+	for _, input := range p.Inputs {
+		b = append(b, byte(input.SigHashFlag))
+		b = append(b, transaction.VarInt(uint64(input.Threshold)).Bytes()...)
+
+		b = append(b, transaction.VarInt(uint64(len(input.RequiredPubKeys))).Bytes()...)
+		for _, pubKey := range input.RequiredPubKeys {
+			b = append(b, pubKey.Compressed()...)
+		}
+
+		for _, values := range []map[string][]byte{input.Signatures, hints(input.Derivations)} {
+			b = append(b, transaction.VarInt(uint64(len(values))).Bytes()...)
+			ids := make([]string, 0, len(values))
+			for id := range values {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			for _, id := range ids {
+				pubKey, err := hex.DecodeString(id)
+				if err != nil {
+					return nil, err
+				}
+				b = append(b, pubKey...)
+				b = append(b, transaction.VarInt(uint64(len(values[id]))).Bytes()...)
+				b = append(b, values[id]...)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// Hex returns the serialised PST as a hex string.
+func (p *PST) Hex() (string, error) {
+	b, err := p.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// NewFromBytes parses a serialised PST. Its signatures are verified as by
+// AddSignature.
+func NewFromBytes(b []byte) (*PST, error) {
+	if len(b) < len(magic)+1 || !bytes.Equal(b[:len(magic)], magic) || b[len(magic)] != formatVersion {
+		return nil, ErrUnknownFormatVersion
+	}
+	r := bytes.NewReader(b[len(magic)+1:])
+
+	ef, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := transaction.NewTransactionFromBytes(ef)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if count != uint64(len(tx.Inputs)) {
+		return nil, ErrInputCountMismatch
+	}
+
+	p := &PST{Tx: tx, Inputs: make([]*Input, count)}
+	signatures := make([]map[*ec.PublicKey][]byte, count)
+	for i := range p.Inputs {
+		flag, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		threshold, err := readVarInt(r)
+		if err != nil {
+			return nil, err
+		}
+		input := &Input{
+			SigHashFlag: sighash.Flag(flag),
+			Threshold:   int(threshold),
+			Signatures:  make(map[string][]byte),
+			Derivations: make(map[string]string),
+		}
+
+		n, err := readVarInt(r)
+		if err != nil {
+			return nil, err
+		}
+		for j := uint64(0); j < n; j++ {
+			pubKey, err := readPubKey(r)
+			if err != nil {
+				return nil, err
+			}
+			input.RequiredPubKeys = append(input.RequiredPubKeys, pubKey)
+		}
+
+		sigs := make(map[*ec.PublicKey][]byte)
+		if err = readKeyed(r, func(pubKey *ec.PublicKey, value []byte) { sigs[pubKey] = value }); err != nil {
+			return nil, err
+		}
+		if err = readKeyed(r, func(pubKey *ec.PublicKey, value []byte) { input.Derivations[keyID(pubKey)] = string(value) }); err != nil {
+			return nil, err
+		}
+		p.Inputs[i] = input
+		signatures[i] = sigs
+		if !p.validThreshold(uint32(i)) {
+			return nil, ErrBadThreshold
+		}
+	}
+
+	if r.Len() != 0 {
+		return nil, ErrTrailingData
+	}
+	for i, sigs := range signatures {
+		for pubKey, sig := range sigs {
+			if err = p.verifySignature(uint32(i), pubKey, sig); err != nil {
+				return nil, err
+			}
+			if err = p.addSignature(p.Inputs[i], pubKey, sig); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+// NewFromHex parses a PST serialised as a hex string.
+func NewFromHex(str string) (*PST, error) {
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromBytes(b)
+}
+
+func hints(derivations map[string]string) map[string][]byte {
+	m := make(map[string][]byte, len(derivations))
+	for id, hint := range derivations {
+		m[id] = []byte(hint)
+	}
+	return m
+}
+
+func readKeyed(r *bytes.Reader, set func(pubKey *ec.PublicKey, value []byte)) error {
+	n, err := readVarInt(r)
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < n; i++ {
+		pubKey, err := readPubKey(r)
+		if err != nil {
+			return err
+		}
+		value, err := readBytes(r)
+		if err != nil {
+			return err
+		}
+		set(pubKey, value)
+	}
+	return nil
+}
+
+func readPubKey(r *bytes.Reader) (*ec.PublicKey, error) {
+	b := make([]byte, 33)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return ec.ParsePubKey(b)
+}
+
+func readVarInt(r *bytes.Reader) (uint64, error) {
+	var v transaction.VarInt
+	if _, err := v.ReadFrom(r); err != nil {
+		return 0, err
+	}
+	return uint64(v), nil
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	if _, err = io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
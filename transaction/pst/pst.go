@@ -0,0 +1,433 @@
+// Package pst implements a partially signed transaction container, in the
+// spirit of PSBT but built on the extended format (EF) of a transaction.
+//
+// A PST carries the transaction together with the source output of every
+// input and per-input signing metadata, so that it can be passed between
+// the parties of a multi-party spend. Each party signs the inputs it can,
+// the resulting PSTs are merged, and once enough signatures are collected
+// the unlocking scripts are finalised and the signed transaction extracted.
+package pst
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+	"github.com/bsv-blockchain/go-sdk/transaction/template"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/timelock"
+)
+
+var (
+	ErrInputIndex           = errors.New("input index out of range")
+	ErrKeyNotRequired       = errors.New("key is not required to sign this input")
+	ErrInvalidSignature     = errors.New("signature does not verify for this input")
+	ErrConflictingSignature = errors.New("input already has a different signature for this key")
+	ErrConflictingUnlock    = errors.New("input already has a different unlocking script")
+	ErrInvalidUnlock        = errors.New("unlocking script does not unlock the input")
+	ErrBadThreshold         = errors.New("input threshold must be at least one and at most the number of required keys")
+	ErrNoPrivateKey         = errors.New("private key not supplied")
+	ErrTimeLockNotSet       = errors.New("transaction lock time or sequence number does not satisfy the timelock")
+	ErrTransactionMismatch  = errors.New("partially signed transactions are for different transactions")
+	ErrSigHashFlagMismatch  = errors.New("partially signed transactions use different sighash flags")
+	ErrUnsupportedScript    = errors.New("cannot finalise input with this locking script")
+	ErrInsufficientSigs     = errors.New("not enough signatures to finalise input")
+	ErrNotFinalised         = errors.New("not every input has an unlocking script")
+	ErrInputCountMismatch   = errors.New("input metadata does not match the transaction inputs")
+	ErrUnknownFormatVersion = errors.New("unknown partially signed transaction format")
+	ErrTrailingData         = errors.New("unexpected data after partially signed transaction")
+)
+
+// finalisable lists the templates whose unlocking scripts consist only of
+// signatures and public keys, and which can therefore be finalised here.
+var finalisable = map[string]bool{
+	template.NamePubKey:     true,
+	template.NamePubKeyHash: true,
+	template.NameMultiSig:   true,
+	template.NamePushDrop:   true,
+	template.NameTimeLock:   true,
+}
+
+// Input holds the signing metadata of a transaction input. Signatures and
+// Derivations are keyed by the hex encoded compressed public key.
+type Input struct {
+	// SigHashFlag is the flag every signature for the input must use.
+	SigHashFlag sighash.Flag
+	// RequiredPubKeys are the keys which may sign the input. It is empty when
+	// the locking script commits only to a public key hash, in which case
+	// the key is added when the input is signed.
+	RequiredPubKeys []*ec.PublicKey
+	// Threshold is the number of signatures needed to spend the input.
+	Threshold int
+	// Signatures are the partial signatures collected so far, each with the
+	// sighash flag appended.
+	Signatures map[string][]byte
+	// Derivations are hints telling signers how to derive the private key for
+	// a public key, such as a BIP32 path or a BRC-43 invoice number.
+	Derivations map[string]string
+}
+
+// PST is a partially signed transaction.
+type PST struct {
+	Tx     *transaction.Transaction
+	Inputs []*Input
+}
+
+// New creates a partially signed transaction. The source output of every
+// input must be set, and the required keys and threshold of each input are
+// taken from its locking script where it can be classified. The lock time,
+// version and sequence numbers of the transaction are set to satisfy any
+// timelocked inputs, since they are covered by every signature.
+func New(tx *transaction.Transaction) (*PST, error) {
+	if tx == nil {
+		return nil, transaction.ErrTxNil
+	}
+	p := &PST{Tx: tx, Inputs: make([]*Input, len(tx.Inputs))}
+	for i, in := range tx.Inputs {
+		if in.SourceTxOutput() == nil {
+			return nil, transaction.ErrEmptyPreviousTx
+		}
+		input := &Input{
+			SigHashFlag: sighash.AllForkID,
+			Threshold:   1,
+			Signatures:  make(map[string][]byte),
+			Derivations: make(map[string]string),
+		}
+		if c, err := template.Classify(in.SourceTxScript()); err == nil {
+			input.RequiredPubKeys = c.PublicKeys
+			if c.Threshold > 0 {
+				input.Threshold = c.Threshold
+			}
+			if c.Template == template.NameTimeLock {
+				if err = timelock.Prepare(tx, uint32(i)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		p.Inputs[i] = input
+	}
+	return p, nil
+}
+
+// SetDerivation records a hint for deriving the private key of pubKey.
+func (p *PST) SetDerivation(inputIndex uint32, pubKey *ec.PublicKey, hint string) error {
+	if int(inputIndex) >= len(p.Inputs) {
+		return ErrInputIndex
+	}
+	p.Inputs[inputIndex].Derivations[keyID(pubKey)] = hint
+	return nil
+}
+
+// SignInput adds a signature for the input made with the key.
+func (p *PST) SignInput(inputIndex uint32, key *ec.PrivateKey) error {
+	if int(inputIndex) >= len(p.Inputs) {
+		return ErrInputIndex
+	}
+	if key == nil {
+		return ErrNoPrivateKey
+	}
+	input := p.Inputs[inputIndex]
+	if !p.canSign(inputIndex, key.PubKey()) {
+		return ErrKeyNotRequired
+	}
+	sh, err := p.Tx.CalcInputSignatureHash(inputIndex, input.SigHashFlag)
+	if err != nil {
+		return err
+	}
+	sig, err := key.Sign(sh)
+	if err != nil {
+		return err
+	}
+	return p.addSignature(input, key.PubKey(), append(sig.Serialize(), uint8(input.SigHashFlag)))
+}
+
+// Sign signs every input which any of the keys is able to sign, returning
+// the number of signatures added.
+func (p *PST) Sign(keys ...*ec.PrivateKey) (int, error) {
+	added := 0
+	for i := range p.Inputs {
+		for _, key := range keys {
+			if key == nil {
+				return added, ErrNoPrivateKey
+			}
+			if !p.canSign(uint32(i), key.PubKey()) {
+				continue
+			}
+			if err := p.SignInput(uint32(i), key); err != nil {
+				return added, err
+			}
+			added++
+		}
+	}
+	return added, nil
+}
+
+// AddSignature adds a signature produced elsewhere, such as by a hardware
+// signer. The signature must include the sighash flag and verify against
+// the input.
+func (p *PST) AddSignature(inputIndex uint32, pubKey *ec.PublicKey, sig []byte) error {
+	if int(inputIndex) >= len(p.Inputs) {
+		return ErrInputIndex
+	}
+	if err := p.verifySignature(inputIndex, pubKey, sig); err != nil {
+		return err
+	}
+	return p.addSignature(p.Inputs[inputIndex], pubKey, sig)
+}
+
+// verifySignature checks that pubKey may sign the input and that sig, with
+// the input's sighash flag appended, is a valid signature for it.
+func (p *PST) verifySignature(inputIndex uint32, pubKey *ec.PublicKey, sig []byte) error {
+	input := p.Inputs[inputIndex]
+	if !p.canSign(inputIndex, pubKey) {
+		return ErrKeyNotRequired
+	}
+	if len(sig) < 2 || sighash.Flag(sig[len(sig)-1]) != input.SigHashFlag {
+		return ErrSigHashFlagMismatch
+	}
+	parsed, err := ec.ParseDERSignature(sig[:len(sig)-1])
+	if err != nil {
+		return err
+	}
+	sh, err := p.Tx.CalcInputSignatureHash(inputIndex, input.SigHashFlag)
+	if err != nil {
+		return err
+	}
+	if !parsed.Verify(sh, pubKey) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (p *PST) addSignature(input *Input, pubKey *ec.PublicKey, sig []byte) error {
+	id := keyID(pubKey)
+	if existing, ok := input.Signatures[id]; ok && !bytes.Equal(existing, sig) {
+		return ErrConflictingSignature
+	}
+	input.Signatures[id] = sig
+	if indexOf(input.RequiredPubKeys, pubKey) < 0 {
+		input.RequiredPubKeys = append(input.RequiredPubKeys, pubKey)
+	}
+	return nil
+}
+
+// canSign reports whether pubKey is one of the keys the input can be signed
+// with, either directly or through a public key hash in its locking script.
+// For locking scripts which cannot be classified, only keys already listed
+// in RequiredPubKeys are accepted.
+func (p *PST) canSign(inputIndex uint32, pubKey *ec.PublicKey) bool {
+	c, err := template.Classify(p.Tx.Inputs[inputIndex].SourceTxScript())
+	if err != nil {
+		return indexOf(p.Inputs[inputIndex].RequiredPubKeys, pubKey) >= 0
+	}
+	if len(c.PublicKeys) > 0 {
+		return indexOf(c.PublicKeys, pubKey) >= 0
+	}
+	pkh := crypto.Hash160(pubKey.Compressed())
+	for _, h := range c.PublicKeyHashes {
+		if bytes.Equal(h, pkh) {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge adds the signatures, derivation hints and unlocking scripts from
+// other, which must be for the same unsigned transaction. Every signature is
+// verified as by AddSignature, and every unlocking script adopted is run
+// against the source output of its input. Nothing is merged if any fails to
+// verify or differs from a signature or unlocking script already held.
+func (p *PST) Merge(other *PST) error {
+	if !bytes.Equal(unsignedBytes(p.Tx), unsignedBytes(other.Tx)) || len(p.Inputs) != len(other.Inputs) {
+		return ErrTransactionMismatch
+	}
+	for i, input := range p.Inputs {
+		theirs := other.Inputs[i]
+		if input.SigHashFlag != theirs.SigHashFlag {
+			return ErrSigHashFlagMismatch
+		}
+		for _, pubKey := range theirs.RequiredPubKeys {
+			sig, ok := theirs.Signatures[keyID(pubKey)]
+			if !ok {
+				continue
+			}
+			if err := p.verifySignature(uint32(i), pubKey, sig); err != nil {
+				return err
+			}
+			if existing, ok := input.Signatures[keyID(pubKey)]; ok && !bytes.Equal(existing, sig) {
+				return ErrConflictingSignature
+			}
+		}
+		ours, theirsIn := p.Tx.Inputs[i], other.Tx.Inputs[i]
+		if !unlocked(theirsIn) {
+			continue
+		}
+		if unlocked(ours) {
+			if !bytes.Equal(*ours.UnlockingScript, *theirsIn.UnlockingScript) {
+				return ErrConflictingUnlock
+			}
+		} else if err := p.verifyUnlock(uint32(i), theirsIn.UnlockingScript); err != nil {
+			return err
+		}
+	}
+	for i, input := range p.Inputs {
+		theirs := other.Inputs[i]
+		for _, pubKey := range theirs.RequiredPubKeys {
+			if sig, ok := theirs.Signatures[keyID(pubKey)]; ok {
+				if err := p.addSignature(input, pubKey, sig); err != nil {
+					return err
+				}
+			} else if indexOf(input.RequiredPubKeys, pubKey) < 0 {
+				input.RequiredPubKeys = append(input.RequiredPubKeys, pubKey)
+			}
+		}
+		for id, hint := range theirs.Derivations {
+			if _, ok := input.Derivations[id]; !ok {
+				input.Derivations[id] = hint
+			}
+		}
+		if tx := other.Tx.Inputs[i]; !unlocked(p.Tx.Inputs[i]) && unlocked(tx) {
+			p.Tx.Inputs[i].UnlockingScript = tx.UnlockingScript
+		}
+	}
+	return nil
+}
+
+// verifyUnlock runs the interpreter on the input with the unlocking script s,
+// leaving the input as it was.
+func (p *PST) verifyUnlock(inputIndex uint32, s *script.Script) error {
+	in := p.Tx.Inputs[inputIndex]
+	prev := in.UnlockingScript
+	in.UnlockingScript = s
+	defer func() { in.UnlockingScript = prev }()
+
+	if err := interpreter.NewEngine().Execute(
+		interpreter.WithTx(p.Tx, int(inputIndex), in.SourceTxOutput()),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	); err != nil {
+		return ErrInvalidUnlock
+	}
+	return nil
+}
+
+// validThreshold reports whether the threshold of the input can be met by
+// its required keys, or by the single key signing for a public key hash.
+func (p *PST) validThreshold(inputIndex uint32) bool {
+	input := p.Inputs[inputIndex]
+	if input.Threshold < 1 {
+		return false
+	}
+	if input.Threshold <= len(input.RequiredPubKeys) {
+		return true
+	}
+	c, err := template.Classify(p.Tx.Inputs[inputIndex].SourceTxScript())
+	return err == nil && len(c.PublicKeyHashes) > 0 && input.Threshold == 1
+}
+
+// Finalise builds the unlocking script of every input which does not yet
+// have one from the collected signatures. Inputs whose locking scripts need
+// more than signatures and public keys, such as hash puzzles, must have
+// their unlocking scripts set directly before finalising. Timelocked inputs
+// are rejected with ErrTimeLockNotSet if the lock time, version or sequence
+// number of the transaction has been changed so that the lock is no longer
+// satisfied.
+func (p *PST) Finalise() error {
+	for i, in := range p.Tx.Inputs {
+		if unlocked(in) {
+			continue
+		}
+		s, err := p.finaliseInput(uint32(i))
+		if err != nil {
+			return err
+		}
+		in.UnlockingScript = s
+	}
+	return nil
+}
+
+func (p *PST) finaliseInput(inputIndex uint32) (*script.Script, error) {
+	c, err := template.Classify(p.Tx.Inputs[inputIndex].SourceTxScript())
+	if err != nil || !finalisable[c.Template] {
+		return nil, ErrUnsupportedScript
+	}
+	if c.Template == template.NameTimeLock && !timelock.Prepared(p.Tx, inputIndex) {
+		return nil, ErrTimeLockNotSet
+	}
+	input := p.Inputs[inputIndex]
+	s := &script.Script{}
+
+	if len(c.PublicKeyHashes) > 0 {
+		for _, pubKey := range input.RequiredPubKeys {
+			sig, ok := input.Signatures[keyID(pubKey)]
+			if !ok || !bytes.Equal(c.PublicKeyHashes[0], crypto.Hash160(pubKey.Compressed())) {
+				continue
+			}
+			if err = s.AppendPushData(sig); err != nil {
+				return nil, err
+			}
+			if err = s.AppendPushData(pubKey.Compressed()); err != nil {
+				return nil, err
+			}
+			return s, nil
+		}
+		return nil, ErrInsufficientSigs
+	}
+
+	if c.Template == template.NameMultiSig {
+		_ = s.AppendOpcodes(script.Op0)
+	}
+	// Signatures must be in the same order as the keys in the locking script.
+	count := 0
+	for _, pubKey := range c.PublicKeys {
+		sig, ok := input.Signatures[keyID(pubKey)]
+		if !ok {
+			continue
+		}
+		if err = s.AppendPushData(sig); err != nil {
+			return nil, err
+		}
+		if count++; count == c.Threshold {
+			return s, nil
+		}
+	}
+	return nil, ErrInsufficientSigs
+}
+
+// Extract returns the signed transaction once every input is finalised.
+func (p *PST) Extract() (*transaction.Transaction, error) {
+	for _, in := range p.Tx.Inputs {
+		if !unlocked(in) {
+			return nil, ErrNotFinalised
+		}
+	}
+	return p.Tx, nil
+}
+
+// unlocked reports whether the input has an unlocking script.
+func unlocked(in *transaction.TransactionInput) bool {
+	return in.UnlockingScript != nil && len(*in.UnlockingScript) > 0
+}
+
+// unsignedBytes serialises the transaction with every unlocking script cleared.
+func unsignedBytes(tx *transaction.Transaction) []byte {
+	return tx.BytesWithClearedInputs(-1, []byte{})
+}
+
+func keyID(pubKey *ec.PublicKey) string {
+	return hex.EncodeToString(pubKey.Compressed())
+}
+
+func indexOf(pubKeys []*ec.PublicKey, pubKey *ec.PublicKey) int {
+	for i, pk := range pubKeys {
+		if pk.IsEqual(pubKey) {
+			return i
+		}
+	}
+	return -1
+}
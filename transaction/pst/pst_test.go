@@ -0,0 +1,274 @@
+package pst_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/pst"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/multisig"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/timelock"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeys(t *testing.T, n int) []*ec.PrivateKey {
+	keys := make([]*ec.PrivateKey, n)
+	for i := range keys {
+		key, err := ec.NewPrivateKey()
+		require.NoError(t, err)
+		keys[i] = key
+	}
+	return keys
+}
+
+// newEscrowTx spends a 2-of-3 multisig output and a P2PKH output owned by payer.
+func newEscrowTx(t *testing.T, signers []*ec.PrivateKey, payer *ec.PrivateKey) *transaction.Transaction {
+	pubKeys := make([]*ec.PublicKey, len(signers))
+	for i, key := range signers {
+		pubKeys[i] = key.PubKey()
+	}
+	msLock, err := multisig.Lock(pubKeys, 2)
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(payer.PubKey(), true)
+	require.NoError(t, err)
+	pkhLock, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, msLock.String(), 5000, nil))
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 1, pkhLock.String(), 1000, nil))
+	require.NoError(t, tx.PayToAddress(address.AddressString, 5900))
+	return tx
+}
+
+func requireValid(t *testing.T, tx *transaction.Transaction) {
+	for i := range tx.Inputs {
+		require.NoError(t, interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, i, tx.Inputs[i].SourceTxOutput()),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		))
+	}
+}
+
+func TestMultiPartySigning(t *testing.T) {
+	signers := newKeys(t, 3)
+	payer := newKeys(t, 1)[0]
+
+	p, err := pst.New(newEscrowTx(t, signers, payer))
+	require.NoError(t, err)
+	require.Equal(t, 2, p.Inputs[0].Threshold)
+	require.Len(t, p.Inputs[0].RequiredPubKeys, 3)
+	require.Empty(t, p.Inputs[1].RequiredPubKeys)
+	require.NoError(t, p.SetDerivation(0, signers[2].PubKey(), "m/0'/2"))
+
+	hexStr, err := p.Hex()
+	require.NoError(t, err)
+
+	// Each party works from its own copy of the serialised PST.
+	sign := func(keys ...*ec.PrivateKey) *pst.PST {
+		copied, err := pst.NewFromHex(hexStr)
+		require.NoError(t, err)
+		_, err = copied.Sign(keys...)
+		require.NoError(t, err)
+		b, err := copied.Bytes()
+		require.NoError(t, err)
+		decoded, err := pst.NewFromBytes(b)
+		require.NoError(t, err)
+		return decoded
+	}
+	fromSigner := sign(signers[2])
+	fromPayer := sign(payer)
+	require.Equal(t, "m/0'/2", fromSigner.Inputs[0].Derivations[signers[2].PubKey().ToDERHex()])
+
+	require.NoError(t, p.Merge(fromSigner))
+	require.ErrorIs(t, p.Finalise(), pst.ErrInsufficientSigs)
+
+	require.NoError(t, p.Merge(fromPayer))
+	require.ErrorIs(t, p.Finalise(), pst.ErrInsufficientSigs)
+	_, err = p.Extract()
+	require.ErrorIs(t, err, pst.ErrNotFinalised)
+
+	require.NoError(t, p.Merge(sign(signers[0])))
+	require.NoError(t, p.Finalise())
+	tx, err := p.Extract()
+	require.NoError(t, err)
+	requireValid(t, tx)
+}
+
+func TestAddSignature(t *testing.T) {
+	signers := newKeys(t, 3)
+	payer := newKeys(t, 1)[0]
+	p, err := pst.New(newEscrowTx(t, signers, payer))
+	require.NoError(t, err)
+
+	// Produce signatures with the multisig template, as an external signer would.
+	unlocker, err := multisig.Unlock(signers[:2], nil)
+	require.NoError(t, err)
+	sigs, _, err := unlocker.Signatures(p.Tx, 0)
+	require.NoError(t, err)
+	require.NoError(t, p.AddSignature(0, signers[0].PubKey(), sigs[0]))
+	require.ErrorIs(t, p.AddSignature(0, signers[2].PubKey(), sigs[1]), pst.ErrInvalidSignature)
+	require.ErrorIs(t, p.AddSignature(0, payer.PubKey(), sigs[1]), pst.ErrKeyNotRequired)
+	require.ErrorIs(t, p.SignInput(1, signers[0]), pst.ErrKeyNotRequired)
+}
+
+func TestMergeMismatch(t *testing.T) {
+	signers := newKeys(t, 3)
+	payer := newKeys(t, 1)[0]
+	a, err := pst.New(newEscrowTx(t, signers, payer))
+	require.NoError(t, err)
+
+	other := newEscrowTx(t, signers, payer)
+	other.LockTime = 1
+	b, err := pst.New(other)
+	require.NoError(t, err)
+	require.ErrorIs(t, a.Merge(b), pst.ErrTransactionMismatch)
+
+	_, err = pst.NewFromBytes([]byte("not a pst"))
+	require.ErrorIs(t, err, pst.ErrUnknownFormatVersion)
+}
+
+func TestMergeVerifiesSignatures(t *testing.T) {
+	signers := newKeys(t, 3)
+	payer := newKeys(t, 1)[0]
+	p, err := pst.New(newEscrowTx(t, signers, payer))
+	require.NoError(t, err)
+	require.ErrorIs(t, p.SignInput(0, nil), pst.ErrNoPrivateKey)
+	hexStr, err := p.Hex()
+	require.NoError(t, err)
+
+	signed, err := pst.NewFromHex(hexStr)
+	require.NoError(t, err)
+	require.NoError(t, signed.SignInput(0, signers[0]))
+	id := signers[0].PubKey().ToDERHex()
+	sig := signed.Inputs[0].Signatures[id]
+
+	// A signature which does not verify is rejected when merged or parsed.
+	forged := bytes.Clone(sig)
+	forged[10] ^= 0xff
+	bad, err := pst.NewFromHex(hexStr)
+	require.NoError(t, err)
+	bad.Inputs[0].Signatures[id] = forged
+	bad.Inputs[0].RequiredPubKeys = signed.Inputs[0].RequiredPubKeys
+	require.ErrorIs(t, p.Merge(bad), pst.ErrInvalidSignature)
+	require.Empty(t, p.Inputs[0].Signatures)
+	b, err := bad.Bytes()
+	require.NoError(t, err)
+	_, err = pst.NewFromBytes(b)
+	require.ErrorIs(t, err, pst.ErrInvalidSignature)
+
+	// The high-S form of the same signature is valid, but conflicts with the
+	// signature already held for the key.
+	malleated, err := pst.NewFromHex(hexStr)
+	require.NoError(t, err)
+	require.NoError(t, malleated.AddSignature(0, signers[0].PubKey(), highS(t, sig)))
+	require.NoError(t, p.Merge(signed))
+	require.ErrorIs(t, p.Merge(malleated), pst.ErrConflictingSignature)
+	require.Equal(t, sig, p.Inputs[0].Signatures[id])
+}
+
+func TestMergeVerifiesUnlockingScripts(t *testing.T) {
+	signers := newKeys(t, 3)
+	payer := newKeys(t, 1)[0]
+	p, err := pst.New(newEscrowTx(t, signers, payer))
+	require.NoError(t, err)
+	hexStr, err := p.Hex()
+	require.NoError(t, err)
+
+	// An unlocking script which does not unlock the input is not adopted.
+	bad, err := pst.NewFromHex(hexStr)
+	require.NoError(t, err)
+	bad.Tx.Inputs[1].UnlockingScript = script.NewFromBytes([]byte{script.OpTRUE})
+	require.ErrorIs(t, p.Merge(bad), pst.ErrInvalidUnlock)
+	require.Nil(t, p.Tx.Inputs[1].UnlockingScript)
+
+	unlocker, err := p2pkh.Unlock(payer, nil)
+	require.NoError(t, err)
+	good, err := pst.NewFromHex(hexStr)
+	require.NoError(t, err)
+	good.Tx.Inputs[1].UnlockingScript, err = unlocker.Sign(good.Tx, 1)
+	require.NoError(t, err)
+	require.NoError(t, p.Merge(good))
+	require.Equal(t, good.Tx.Inputs[1].UnlockingScript, p.Tx.Inputs[1].UnlockingScript)
+}
+
+func TestThresholdFromWire(t *testing.T) {
+	signers := newKeys(t, 3)
+	payer := newKeys(t, 1)[0]
+	p, err := pst.New(newEscrowTx(t, signers, payer))
+	require.NoError(t, err)
+
+	for _, threshold := range []int{0, 4} {
+		p.Inputs[0].Threshold = threshold
+		b, err := p.Bytes()
+		require.NoError(t, err)
+		_, err = pst.NewFromBytes(b)
+		require.ErrorIs(t, err, pst.ErrBadThreshold, "threshold %d", threshold)
+	}
+
+	// A P2PKH input has no required keys until it is signed.
+	p.Inputs[0].Threshold = 3
+	b, err := p.Bytes()
+	require.NoError(t, err)
+	decoded, err := pst.NewFromBytes(b)
+	require.NoError(t, err)
+	require.Empty(t, decoded.Inputs[1].RequiredPubKeys)
+	require.Equal(t, 1, decoded.Inputs[1].Threshold)
+}
+
+// highS re-encodes a signature with its S value negated, which verifies
+// against the same key and message.
+func highS(t *testing.T, sig []byte) []byte {
+	parsed, err := ec.ParseDERSignature(sig[:len(sig)-1])
+	require.NoError(t, err)
+	s := new(big.Int).Sub(ec.S256().N, parsed.S)
+	integer := func(n *big.Int) []byte {
+		b := n.Bytes()
+		if b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return append([]byte{0x02, byte(len(b))}, b...)
+	}
+	body := append(integer(parsed.R), integer(s)...)
+	return append(append([]byte{0x30, byte(len(body))}, body...), sig[len(sig)-1])
+}
+
+func TestTimeLockInput(t *testing.T) {
+	owner := newKeys(t, 1)[0]
+	address, err := script.NewAddressFromPublicKey(owner.PubKey(), true)
+	require.NoError(t, err)
+	lock, err := timelock.LockAbsolute(800000, address)
+	require.NoError(t, err)
+
+	newPST := func() *pst.PST {
+		tx := transaction.NewTransaction()
+		require.NoError(t, tx.AddInputFrom(
+			"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, lock.String(), 1000, nil))
+		require.NoError(t, tx.PayToAddress(address.AddressString, 900))
+		p, err := pst.New(tx)
+		require.NoError(t, err)
+		_, err = p.Sign(owner)
+		require.NoError(t, err)
+		return p
+	}
+
+	// The lock time and sequence number are set before anything is signed.
+	p := newPST()
+	require.Equal(t, uint32(800000), p.Tx.LockTime)
+	require.NoError(t, p.Finalise())
+	tx, err := p.Extract()
+	require.NoError(t, err)
+	requireValid(t, tx)
+
+	p = newPST()
+	p.Tx.Inputs[0].SequenceNumber = transaction.MaxTxInSequenceNum
+	require.ErrorIs(t, p.Finalise(), pst.ErrTimeLockNotSet)
+}
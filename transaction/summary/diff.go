@@ -0,0 +1,149 @@
+package summary
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// Difference is a single difference between two transactions. Field is a
+// path such as "locktime", "inputs[0].sequence" or "outputs[2]", and Old
+// and New describe the value in each transaction, empty when it is absent.
+type Difference struct {
+	Field string
+	Old   string
+	New   string
+}
+
+func (d Difference) String() string {
+	switch {
+	case d.Old == "":
+		return fmt.Sprintf("%s: added %s", d.Field, d.New)
+	case d.New == "":
+		return fmt.Sprintf("%s: removed %s", d.Field, d.Old)
+	}
+	return fmt.Sprintf("%s: %s -> %s", d.Field, d.Old, d.New)
+}
+
+// Differences lists the differences between two transactions.
+type Differences []Difference
+
+// String returns the differences one per line.
+func (d Differences) String() string {
+	lines := make([]string, len(d))
+	for i, diff := range d {
+		lines[i] = diff.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff compares two versions of a transaction, such as a transaction and
+// the signed replacement for it, and returns the fields which differ.
+// Inputs and outputs are compared by index. Unlocking scripts are only
+// reported when they are present in both and differ, so signing a
+// transaction does not show as a change. Fee changes are reported when the
+// value of every input is known in both.
+func Diff(old, updated *transaction.Transaction) (Differences, error) {
+	if old == nil || updated == nil {
+		return nil, ErrNilTransaction
+	}
+	var d Differences
+	add := func(field string, o, n any) {
+		d = append(d, Difference{Field: field, Old: fmt.Sprint(o), New: fmt.Sprint(n)})
+	}
+
+	if old.Version != updated.Version {
+		add("version", old.Version, updated.Version)
+	}
+	if old.LockTime != updated.LockTime {
+		add("locktime", old.LockTime, updated.LockTime)
+	}
+
+	for i := 0; i < max(len(old.Inputs), len(updated.Inputs)); i++ {
+		field := fmt.Sprintf("inputs[%d]", i)
+		if i >= len(updated.Inputs) {
+			d = append(d, Difference{Field: field, Old: outpoint(old.Inputs[i])})
+			continue
+		}
+		if i >= len(old.Inputs) {
+			d = append(d, Difference{Field: field, New: outpoint(updated.Inputs[i])})
+			continue
+		}
+		o, n := old.Inputs[i], updated.Inputs[i]
+		if outpoint(o) != outpoint(n) {
+			add(field+".outpoint", outpoint(o), outpoint(n))
+		}
+		if o.SequenceNumber != n.SequenceNumber {
+			add(field+".sequence", o.SequenceNumber, n.SequenceNumber)
+		}
+		if o.UnlockingScript != nil && n.UnlockingScript != nil && len(*o.UnlockingScript) > 0 &&
+			len(*n.UnlockingScript) > 0 && !bytes.Equal(*o.UnlockingScript, *n.UnlockingScript) {
+			add(field+".unlockingScript", o.UnlockingScript.String(), n.UnlockingScript.String())
+		}
+	}
+
+	for i := 0; i < max(len(old.Outputs), len(updated.Outputs)); i++ {
+		field := fmt.Sprintf("outputs[%d]", i)
+		if i >= len(updated.Outputs) {
+			d = append(d, Difference{Field: field, Old: describeOutput(old.Outputs[i])})
+			continue
+		}
+		if i >= len(old.Outputs) {
+			d = append(d, Difference{Field: field, New: describeOutput(updated.Outputs[i])})
+			continue
+		}
+		o, n := old.Outputs[i], updated.Outputs[i]
+		if o.Satoshis != n.Satoshis {
+			add(field+".satoshis", o.Satoshis, n.Satoshis)
+		}
+		if !bytes.Equal(scriptBytes(o.LockingScript), scriptBytes(n.LockingScript)) {
+			add(field+".lockingScript", describeScript(o.LockingScript), describeScript(n.LockingScript))
+		}
+	}
+
+	oldSummary, err := New(old)
+	if err != nil {
+		return nil, err
+	}
+	newSummary, err := New(updated)
+	if err != nil {
+		return nil, err
+	}
+	if oldSummary.Complete && newSummary.Complete && oldSummary.Fee != newSummary.Fee {
+		add("fee", oldSummary.Fee, newSummary.Fee)
+	}
+	return d, nil
+}
+
+func outpoint(in *transaction.TransactionInput) string {
+	txid := ""
+	if in.SourceTXID != nil {
+		txid = in.SourceTXID.String()
+	}
+	return fmt.Sprintf("%s:%d", txid, in.SourceTxOutIndex)
+}
+
+func scriptBytes(s *script.Script) []byte {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func describeScript(s *script.Script) string {
+	templateName, address, _, _ := describe(s, nil)
+	if address != "" {
+		return templateName + " " + address
+	}
+	if s == nil {
+		return templateName
+	}
+	return templateName + " " + s.String()
+}
+
+func describeOutput(o *transaction.TransactionOutput) string {
+	return fmt.Sprintf("%d sats to %s", o.Satoshis, describeScript(o.LockingScript))
+}
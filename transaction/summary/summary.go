@@ -0,0 +1,278 @@
+// Package summary describes transactions for review before they are signed
+// or broadcast: where the value comes from and goes to, the fee paid and how
+// a transaction differs from another version of it.
+package summary
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template"
+)
+
+// NonStandard is the template name of scripts which match no known template.
+const NonStandard = "nonstandard"
+
+var ErrNilTransaction = errors.New("transaction is nil")
+
+// Input summarises a transaction input.
+type Input struct {
+	Index          int
+	SourceTXID     string
+	SourceOutIndex uint32
+	// Satoshis is nil when the source output is unknown.
+	Satoshis *uint64
+	// Template and Address describe the source output, when known.
+	Template string
+	Address  string
+	// Ours reports whether the source output pays one of our addresses.
+	Ours bool
+	// Shared reports whether the source output needs signatures from some
+	// of our keys, but fewer of them than its threshold.
+	Shared bool
+	// Signed reports whether the input has an unlocking script.
+	Signed bool
+}
+
+// Output summarises a transaction output.
+type Output struct {
+	Index    int
+	Satoshis uint64
+	Template string
+	// Address is set for outputs locked to a single public key or public key hash.
+	Address string
+	// Change reports whether the output is marked as change.
+	Change bool
+	// Ours reports whether the output pays one of our addresses. For
+	// multisig outputs, our keys must meet the threshold.
+	Ours bool
+	// Shared reports whether the output needs signatures from some of our
+	// keys, but fewer of them than its threshold. Shared outputs are not
+	// ours, and count as payments unless marked as change.
+	Shared bool
+}
+
+// Payment reports whether the output pays value away, that is it is
+// neither change nor paid to one of our addresses.
+func (o *Output) Payment() bool {
+	return !o.Change && !o.Ours
+}
+
+// Summary summarises a transaction. Addresses are given in mainnet form.
+type Summary struct {
+	TxID     string
+	Version  uint32
+	LockTime uint32
+	Inputs   []Input
+	Outputs  []Output
+	// Size is the size of the transaction in bytes, estimated from the
+	// unlocking script templates of unsigned inputs.
+	Size int
+	// Complete reports whether the value of every input is known. The
+	// totals, fee and net value below are only meaningful when it is.
+	Complete bool
+	TotalIn  uint64
+	TotalOut uint64
+	Fee      uint64
+	// FeeRate is the fee in satoshis per kilobyte.
+	FeeRate float64
+	// Paid is the value of the payment outputs and Change the value of the
+	// change outputs.
+	Paid   uint64
+	Change uint64
+	// Net is the value received by our addresses less the value they spend,
+	// negative when the transaction spends our funds.
+	Net int64
+}
+
+// New summarises the transaction. Inputs and outputs which pay one of the
+// ours addresses are marked as ours and counted in the net value.
+func New(tx *transaction.Transaction, ours ...*script.Address) (*Summary, error) {
+	if tx == nil {
+		return nil, ErrNilTransaction
+	}
+	s := &Summary{
+		TxID:     tx.TxID().String(),
+		Version:  tx.Version,
+		LockTime: tx.LockTime,
+		Inputs:   make([]Input, len(tx.Inputs)),
+		Outputs:  make([]Output, len(tx.Outputs)),
+		Complete: true,
+	}
+
+	size, err := tx.EstimateSize()
+	if err != nil {
+		size = tx.Size()
+	}
+	s.Size = size
+
+	for i, in := range tx.Inputs {
+		si := Input{
+			Index:          i,
+			SourceOutIndex: in.SourceTxOutIndex,
+			Signed:         in.UnlockingScript != nil && len(*in.UnlockingScript) > 0,
+		}
+		if in.SourceTXID != nil {
+			si.SourceTXID = in.SourceTXID.String()
+		}
+		if o := in.SourceTxOutput(); o != nil {
+			sats := o.Satoshis
+			si.Satoshis = &sats
+			si.Template, si.Address, si.Ours, si.Shared = describe(o.LockingScript, ours)
+			s.TotalIn += sats
+			if si.Ours {
+				s.Net -= int64(sats)
+			}
+		} else {
+			s.Complete = false
+		}
+		s.Inputs[i] = si
+	}
+
+	for i, o := range tx.Outputs {
+		so := Output{Index: i, Satoshis: o.Satoshis, Change: o.Change}
+		so.Template, so.Address, so.Ours, so.Shared = describe(o.LockingScript, ours)
+		s.TotalOut += o.Satoshis
+		switch {
+		case so.Change:
+			s.Change += o.Satoshis
+		case so.Payment():
+			s.Paid += o.Satoshis
+		}
+		if so.Ours {
+			s.Net += int64(o.Satoshis)
+		}
+		s.Outputs[i] = so
+	}
+
+	if s.Complete && s.TotalIn >= s.TotalOut {
+		s.Fee = s.TotalIn - s.TotalOut
+		if s.Size > 0 {
+			s.FeeRate = float64(s.Fee) * 1000 / float64(s.Size)
+		}
+	}
+	return s, nil
+}
+
+// describe returns the template name and address of a locking script, and
+// whether it is ours or shared: whether our addresses hold at least as many
+// of its keys as its threshold, or only some of them.
+func describe(s *script.Script, ours []*script.Address) (string, string, bool, bool) {
+	if s == nil {
+		return NonStandard, "", false, false
+	}
+	c, err := template.Classify(s)
+	if err != nil {
+		return NonStandard, "", false, false
+	}
+
+	hashes := c.PublicKeyHashes
+	for _, pub := range rawPublicKeys(s, c.PublicKeys) {
+		hashes = append(hashes, crypto.Hash160(pub))
+	}
+	var address string
+	if len(hashes) == 1 {
+		if a, err := script.NewAddressFromPublicKeyHash(hashes[0], true); err == nil {
+			address = a.AddressString
+		}
+	}
+	matched := 0
+	for _, h := range hashes {
+		for _, a := range ours {
+			if bytes.Equal(h, a.PublicKeyHash) {
+				matched++
+				break
+			}
+		}
+	}
+	threshold := max(c.Threshold, 1)
+	return c.Template, address, matched >= threshold, matched > 0 && matched < threshold
+}
+
+// rawPublicKeys returns the pushes of the script which encode the keys, in
+// the form they appear in it, so that uncompressed keys hash to their own
+// addresses.
+func rawPublicKeys(s *script.Script, keys []*ec.PublicKey) [][]byte {
+	if len(keys) == 0 {
+		return nil
+	}
+	chunks, err := s.Chunks()
+	if err != nil {
+		return nil
+	}
+	raw := make([][]byte, 0, len(keys))
+	for _, chunk := range chunks {
+		if len(raw) == len(keys) {
+			break
+		}
+		if len(chunk.Data) != 33 && len(chunk.Data) != 65 {
+			continue
+		}
+		pub, err := ec.ParsePubKey(chunk.Data)
+		if err != nil {
+			continue
+		}
+		for _, key := range keys {
+			if key.IsEqual(pub) {
+				raw = append(raw, chunk.Data)
+				break
+			}
+		}
+	}
+	return raw
+}
+
+// String returns a human readable description of the transaction.
+func (s *Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Transaction %s (version %d, lock time %d, %d bytes)\n", s.TxID, s.Version, s.LockTime, s.Size)
+	fmt.Fprintf(&b, "Inputs:\n")
+	for _, in := range s.Inputs {
+		value := "unknown value"
+		if in.Satoshis != nil {
+			value = fmt.Sprintf("%d sats", *in.Satoshis)
+		}
+		fmt.Fprintf(&b, "  %d: %s:%d %s%s\n", in.Index, in.SourceTXID, in.SourceOutIndex, value, label(in.Template, in.Address, in.Ours, in.Shared, false))
+	}
+	fmt.Fprintf(&b, "Outputs:\n")
+	for _, o := range s.Outputs {
+		fmt.Fprintf(&b, "  %d: %d sats%s\n", o.Index, o.Satoshis, label(o.Template, o.Address, o.Ours, o.Shared, o.Change))
+	}
+	if s.Complete {
+		fmt.Fprintf(&b, "Fee: %d sats (%.2f sats/kB)\n", s.Fee, s.FeeRate)
+		fmt.Fprintf(&b, "Paid: %d sats, change: %d sats, net: %+d sats", s.Paid, s.Change, s.Net)
+	} else {
+		fmt.Fprintf(&b, "Fee: unknown, source outputs missing\n")
+		fmt.Fprintf(&b, "Paid: %d sats, change: %d sats", s.Paid, s.Change)
+	}
+	return b.String()
+}
+
+func label(templateName, address string, ours, shared, change bool) string {
+	var parts []string
+	if templateName != "" {
+		parts = append(parts, templateName)
+	}
+	if address != "" {
+		parts = append(parts, address)
+	}
+	if ours {
+		parts = append(parts, "ours")
+	}
+	if shared {
+		parts = append(parts, "shared")
+	}
+	if change {
+		parts = append(parts, "change")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,160 @@
+package summary_test
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/summary"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/multisig"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/require"
+)
+
+const sourceTxID = "45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d"
+
+type wallet struct {
+	key     *ec.PrivateKey
+	address *script.Address
+	lock    *script.Script
+}
+
+func newWallet(t *testing.T) *wallet {
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(key.PubKey(), true)
+	require.NoError(t, err)
+	lock, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	return &wallet{key: key, address: address, lock: lock}
+}
+
+func newPayment(t *testing.T, from, to *wallet) *transaction.Transaction {
+	unlocker, err := p2pkh.Unlock(from.key, nil)
+	require.NoError(t, err)
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(sourceTxID, 0, from.lock.String(), 10000, unlocker))
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: 3000, LockingScript: to.lock})
+	require.NoError(t, tx.AddOpReturnOutput([]byte("memo")))
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: 6900, LockingScript: from.lock, Change: true})
+	return tx
+}
+
+func TestSummary(t *testing.T) {
+	me, them := newWallet(t), newWallet(t)
+	tx := newPayment(t, me, them)
+
+	s, err := summary.New(tx, me.address)
+	require.NoError(t, err)
+	require.True(t, s.Complete)
+	require.Equal(t, uint64(10000), s.TotalIn)
+	require.Equal(t, uint64(9900), s.TotalOut)
+	require.Equal(t, uint64(100), s.Fee)
+	require.Equal(t, uint64(3000), s.Paid)
+	require.Equal(t, uint64(6900), s.Change)
+	require.Equal(t, int64(-3100), s.Net)
+	require.InDelta(t, float64(100*1000)/float64(s.Size), s.FeeRate, 0.001)
+
+	require.True(t, s.Inputs[0].Ours)
+	require.False(t, s.Inputs[0].Signed)
+	require.Equal(t, me.address.AddressString, s.Inputs[0].Address)
+	require.Equal(t, script.ScriptTypePubKeyHash, s.Inputs[0].Template)
+
+	require.True(t, s.Outputs[0].Payment())
+	require.Equal(t, them.address.AddressString, s.Outputs[0].Address)
+	require.Equal(t, script.ScriptTypeNullData, s.Outputs[1].Template)
+	require.True(t, s.Outputs[2].Change)
+	require.True(t, s.Outputs[2].Ours)
+	require.Contains(t, s.String(), "net: -3100 sats")
+
+	// Without the source output the value is unknown.
+	tx.Inputs[0].SetSourceTxOutput(nil)
+	s, err = summary.New(tx)
+	require.NoError(t, err)
+	require.False(t, s.Complete)
+	require.Nil(t, s.Inputs[0].Satoshis)
+	require.Zero(t, s.Fee)
+}
+
+func TestSummaryMultiSig(t *testing.T) {
+	me, partner, them := newWallet(t), newWallet(t), newWallet(t)
+	both, err := multisig.Lock([]*ec.PublicKey{me.key.PubKey(), partner.key.PubKey()}, 2)
+	require.NoError(t, err)
+	either, err := multisig.Lock([]*ec.PublicKey{me.key.PubKey(), them.key.PubKey()}, 1)
+	require.NoError(t, err)
+
+	tx := newPayment(t, me, them)
+	tx.Outputs[0].LockingScript = both
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: 0, LockingScript: either})
+
+	// A 2-of-2 output is only ours with both keys.
+	s, err := summary.New(tx, me.address)
+	require.NoError(t, err)
+	require.False(t, s.Outputs[0].Ours)
+	require.True(t, s.Outputs[0].Shared)
+	require.True(t, s.Outputs[0].Payment())
+	require.True(t, s.Outputs[3].Ours)
+	require.False(t, s.Outputs[3].Shared)
+	require.Equal(t, uint64(3000), s.Paid)
+	require.Contains(t, s.String(), "shared")
+
+	s, err = summary.New(tx, me.address, partner.address)
+	require.NoError(t, err)
+	require.True(t, s.Outputs[0].Ours)
+	require.False(t, s.Outputs[0].Shared)
+	require.Zero(t, s.Paid)
+}
+
+func TestSummaryUncompressedPubKey(t *testing.T) {
+	me, them := newWallet(t), newWallet(t)
+	uncompressed, err := script.NewAddressFromPublicKeyHash(crypto.Hash160(me.key.PubKey().Uncompressed()), true)
+	require.NoError(t, err)
+	lock := &script.Script{}
+	require.NoError(t, lock.AppendPushData(me.key.PubKey().Uncompressed()))
+	require.NoError(t, lock.AppendOpcodes(script.OpCHECKSIG))
+
+	tx := newPayment(t, them, me)
+	tx.Outputs[0].LockingScript = lock
+
+	// The output is matched by the address of the key as it appears in the
+	// script, not that of its compressed form.
+	s, err := summary.New(tx, uncompressed)
+	require.NoError(t, err)
+	require.Equal(t, script.ScriptTypePubKey, s.Outputs[0].Template)
+	require.Equal(t, uncompressed.AddressString, s.Outputs[0].Address)
+	require.True(t, s.Outputs[0].Ours)
+
+	s, err = summary.New(tx, me.address)
+	require.NoError(t, err)
+	require.False(t, s.Outputs[0].Ours)
+}
+
+func TestDiff(t *testing.T) {
+	me, them := newWallet(t), newWallet(t)
+	original := newPayment(t, me, them)
+	require.NoError(t, original.Sign())
+
+	// Signing a copy changes nothing which is reported.
+	signed := newPayment(t, me, them)
+	require.NoError(t, signed.Sign())
+	d, err := summary.Diff(original, signed)
+	require.NoError(t, err)
+	require.Empty(t, d)
+
+	replacement := newPayment(t, me, them)
+	replacement.Inputs[0].SequenceNumber = 1
+	replacement.Outputs[2].Satoshis = 6800
+	replacement.AddOutput(&transaction.TransactionOutput{Satoshis: 50, LockingScript: them.lock})
+
+	d, err = summary.Diff(original, replacement)
+	require.NoError(t, err)
+	require.Equal(t, summary.Differences{
+		{Field: "inputs[0].sequence", Old: "4294967295", New: "1"},
+		{Field: "outputs[2].satoshis", Old: "6900", New: "6800"},
+		{Field: "outputs[3]", New: "50 sats to pubkeyhash " + them.address.AddressString},
+		{Field: "fee", Old: "100", New: "150"},
+	}, d)
+	require.Contains(t, d.String(), "outputs[3]: added 50 sats")
+}
@@ -0,0 +1,133 @@
+package hashpuzzle
+
+import (
+	"errors"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+var (
+	ErrBadSecretHash    = errors.New("invalid secret hash")
+	ErrBadPublicKeyHash = errors.New("invalid public key hash")
+	ErrNoPrivateKey     = errors.New("private key not supplied")
+	ErrNoSecret         = errors.New("secret not supplied")
+	ErrNotHashPuzzle    = errors.New("script is not a hash puzzle output")
+)
+
+// Lock creates a hash puzzle locking script which requires the preimage of
+// secretHash (a HASH160) as well as a signature from the owner of the address:
+//
+// OP_HASH160 <secretHash> OP_EQUALVERIFY OP_DUP OP_HASH160 <pkh> OP_EQUALVERIFY OP_CHECKSIG
+//
+// This is the same script produced by Transaction.AddHashPuzzleOutput.
+func Lock(secretHash []byte, a *script.Address) (*script.Script, error) {
+	if len(secretHash) != 20 {
+		return nil, ErrBadSecretHash
+	}
+	if len(a.PublicKeyHash) != 20 {
+		return nil, ErrBadPublicKeyHash
+	}
+	b := make([]byte, 0, 48)
+	b = append(b, script.OpHASH160, script.OpDATA20)
+	b = append(b, secretHash...)
+	b = append(b, script.OpEQUALVERIFY, script.OpDUP, script.OpHASH160, script.OpDATA20)
+	b = append(b, a.PublicKeyHash...)
+	b = append(b, script.OpEQUALVERIFY, script.OpCHECKSIG)
+	s := script.Script(b)
+	return &s, nil
+}
+
+// LockSecret creates a hash puzzle locking script from the secret itself.
+func LockSecret(secret []byte, a *script.Address) (*script.Script, error) {
+	return Lock(crypto.Hash160(secret), a)
+}
+
+// Decode returns the secret hash and the public key hash of a hash puzzle locking script.
+func Decode(s *script.Script) (secretHash []byte, publicKeyHash []byte, err error) {
+	if s == nil {
+		return nil, nil, ErrNotHashPuzzle
+	}
+	b := []byte(*s)
+	if len(b) != 48 ||
+		b[0] != script.OpHASH160 ||
+		b[1] != script.OpDATA20 ||
+		b[22] != script.OpEQUALVERIFY ||
+		b[23] != script.OpDUP ||
+		b[24] != script.OpHASH160 ||
+		b[25] != script.OpDATA20 ||
+		b[46] != script.OpEQUALVERIFY ||
+		b[47] != script.OpCHECKSIG {
+		return nil, nil, ErrNotHashPuzzle
+	}
+	return b[2:22], b[26:46], nil
+}
+
+func Unlock(key *ec.PrivateKey, secret []byte, sigHashFlag *sighash.Flag) (*HashPuzzle, error) {
+	if key == nil {
+		return nil, ErrNoPrivateKey
+	}
+	if len(secret) == 0 {
+		return nil, ErrNoSecret
+	}
+	if sigHashFlag == nil {
+		shf := sighash.AllForkID
+		sigHashFlag = &shf
+	}
+	return &HashPuzzle{
+		PrivateKey:  key,
+		Secret:      secret,
+		SigHashFlag: sigHashFlag,
+	}, nil
+}
+
+type HashPuzzle struct {
+	PrivateKey  *ec.PrivateKey
+	Secret      []byte
+	SigHashFlag *sighash.Flag
+}
+
+// Sign produces the unlocking script <sig> <pubkey> <secret>.
+func (p *HashPuzzle) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	if tx.Inputs[inputIndex].SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+
+	sh, err := tx.CalcInputSignatureHash(inputIndex, *p.SigHashFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := p.PrivateKey.Sign(sh)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBuf := make([]byte, 0)
+	sigBuf = append(sigBuf, sig.Serialize()...)
+	sigBuf = append(sigBuf, uint8(*p.SigHashFlag))
+
+	s := &script.Script{}
+	if err = s.AppendPushData(sigBuf); err != nil {
+		return nil, err
+	} else if err = s.AppendPushData(p.PrivateKey.PubKey().Compressed()); err != nil {
+		return nil, err
+	} else if err = s.AppendPushData(p.Secret); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// EstimateLength returns the worst case length of a P2PKH unlocking script
+// plus the push of the secret.
+func (p *HashPuzzle) EstimateLength(_ *transaction.Transaction, inputIndex uint32) uint32 {
+	prefix, err := script.PushDataPrefix(p.Secret)
+	if err != nil {
+		return transaction.P2PKHUnlockingScriptLength
+	}
+	return transaction.P2PKHUnlockingScriptLength + uint32(len(prefix)+len(p.Secret))
+}
@@ -0,0 +1,68 @@
+package hashpuzzle_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/hashpuzzle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashPuzzle(t *testing.T) {
+	priv, err := ec.PrivateKeyFromWif("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+	secret := "my super secret preimage"
+
+	// The puzzle output is created the way the transaction package does it.
+	sourceTx := transaction.NewTransaction()
+	require.NoError(t, sourceTx.AddHashPuzzleOutput(secret, hex.EncodeToString(address.PublicKeyHash), 10000))
+
+	lockingScript, err := hashpuzzle.LockSecret([]byte(secret), address)
+	require.NoError(t, err)
+	require.Equal(t, sourceTx.Outputs[0].LockingScript, lockingScript)
+
+	secretHash, pkh, err := hashpuzzle.Decode(lockingScript)
+	require.NoError(t, err)
+	require.Equal(t, crypto.Hash160([]byte(secret)), secretHash)
+	require.Equal(t, []byte(address.PublicKeyHash), pkh)
+
+	t.Run("valid secret", func(t *testing.T) {
+		unlocker, err := hashpuzzle.Unlock(priv, []byte(secret), nil)
+		require.NoError(t, err)
+
+		tx := transaction.NewTransaction()
+		tx.AddInputFromTx(sourceTx, 0, unlocker)
+		require.NoError(t, tx.PayToAddress(address.AddressString, 9000))
+		require.NoError(t, tx.Sign())
+		require.LessOrEqual(t, uint32(len(*tx.Inputs[0].UnlockingScript)), unlocker.EstimateLength(tx, 0))
+
+		require.NoError(t, interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		))
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		unlocker, err := hashpuzzle.Unlock(priv, []byte("not the secret"), nil)
+		require.NoError(t, err)
+
+		tx := transaction.NewTransaction()
+		tx.AddInputFromTx(sourceTx, 0, unlocker)
+		require.NoError(t, tx.PayToAddress(address.AddressString, 9000))
+		require.NoError(t, tx.Sign())
+
+		require.Error(t, interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		))
+	})
+}
@@ -0,0 +1,285 @@
+package multisig
+
+import (
+	"bytes"
+	"errors"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+// MaxPublicKeys is the largest number of public keys that can be encoded
+// using the small integer opcodes OP_1 to OP_16.
+const MaxPublicKeys = 16
+
+var (
+	ErrNoPublicKeys          = errors.New("no public keys supplied")
+	ErrTooManyPublicKeys     = errors.New("too many public keys supplied")
+	ErrBadThreshold          = errors.New("threshold must be between 1 and the number of public keys")
+	ErrNoPrivateKeys         = errors.New("private keys not supplied")
+	ErrNotMultiSig           = errors.New("script is not a multisig output")
+	ErrInsufficientSignature = errors.New("not enough private keys to satisfy threshold")
+	ErrNoSignatures          = errors.New("signatures not supplied")
+	ErrTooManySignatures     = errors.New("more signatures supplied than the threshold requires")
+	ErrBadSignatureOrder     = errors.New("signatures do not verify against the public keys in order")
+)
+
+// Lock creates an m-of-n bare multisig locking script of the form
+// OP_m <pubkey1> ... <pubkeyn> OP_n OP_CHECKMULTISIG.
+func Lock(pubKeys []*ec.PublicKey, m int) (*script.Script, error) {
+	if len(pubKeys) == 0 {
+		return nil, ErrNoPublicKeys
+	}
+	if len(pubKeys) > MaxPublicKeys {
+		return nil, ErrTooManyPublicKeys
+	}
+	if m < 1 || m > len(pubKeys) {
+		return nil, ErrBadThreshold
+	}
+
+	s := &script.Script{}
+	_ = s.AppendOpcodes(script.Op1 + byte(m-1))
+	for _, pk := range pubKeys {
+		if err := s.AppendPushData(pk.Compressed()); err != nil {
+			return nil, err
+		}
+	}
+	_ = s.AppendOpcodes(script.Op1+byte(len(pubKeys)-1), script.OpCHECKMULTISIG)
+
+	return s, nil
+}
+
+// Decode extracts the public keys and the signature threshold from
+// a bare multisig locking script.
+func Decode(s *script.Script) (pubKeys []*ec.PublicKey, m int, err error) {
+	if s == nil || !s.IsMultiSigOut() {
+		return nil, 0, ErrNotMultiSig
+	}
+
+	parts, err := s.Chunks()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	m = smallInt(parts[0].Op)
+	n := smallInt(parts[len(parts)-2].Op)
+	if n != len(parts)-3 || m < 1 || m > n {
+		return nil, 0, ErrNotMultiSig
+	}
+
+	pubKeys = make([]*ec.PublicKey, 0, n)
+	for _, part := range parts[1 : len(parts)-2] {
+		pk, err := ec.ParsePubKey(part.Data)
+		if err != nil {
+			return nil, 0, err
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+
+	return pubKeys, m, nil
+}
+
+// Unlock creates an unlocking template for a bare multisig output. The
+// private keys may belong to several signers and can be supplied in any
+// order; signatures are emitted in the order of the public keys found
+// in the locking script being spent.
+func Unlock(keys []*ec.PrivateKey, sigHashFlag *sighash.Flag) (*MultiSig, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoPrivateKeys
+	}
+	for _, key := range keys {
+		if key == nil {
+			return nil, ErrNoPrivateKeys
+		}
+	}
+	if sigHashFlag == nil {
+		shf := sighash.AllForkID
+		sigHashFlag = &shf
+	}
+	return &MultiSig{
+		PrivateKeys: keys,
+		SigHashFlag: sigHashFlag,
+	}, nil
+}
+
+type MultiSig struct {
+	PrivateKeys []*ec.PrivateKey
+	SigHashFlag *sighash.Flag
+}
+
+// Sign produces the unlocking script OP_0 <sig1> ... <sigm>, where the
+// leading OP_0 is the dummy element consumed by OP_CHECKMULTISIG.
+func (p *MultiSig) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	if tx.Inputs[inputIndex].SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+
+	sigs, m, err := p.Signatures(tx, inputIndex)
+	if err != nil {
+		return nil, err
+	}
+	if len(sigs) < m {
+		return nil, ErrInsufficientSignature
+	}
+
+	s := &script.Script{}
+	_ = s.AppendOpcodes(script.Op0)
+	for _, sig := range sigs[:m] {
+		if err = s.AppendPushData(sig); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Signatures returns the signatures (with the sighash flag appended) that
+// the held private keys can produce for the given input, ordered by the
+// position of their public key in the locking script, together with the
+// threshold m of the script. Fewer than m signatures may be returned, which
+// allows each signer to produce a partial set to be combined later.
+func (p *MultiSig) Signatures(tx *transaction.Transaction, inputIndex uint32) ([][]byte, int, error) {
+	pubKeys, m, err := Decode(tx.Inputs[inputIndex].SourceTxScript())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sh, err := tx.CalcInputSignatureHash(inputIndex, *p.SigHashFlag)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sigs := make([][]byte, 0, m)
+	for _, pk := range pubKeys {
+		key := p.keyFor(pk)
+		if key == nil {
+			continue
+		}
+		sig, err := key.Sign(sh)
+		if err != nil {
+			return nil, 0, err
+		}
+		sigs = append(sigs, append(sig.Serialize(), uint8(*p.SigHashFlag)))
+	}
+
+	return sigs, m, nil
+}
+
+// EstimateLength returns the worst case length of the unlocking script: the
+// OP_0 dummy followed by a signature push for each of the m required
+// signatures.
+func (p *MultiSig) EstimateLength(tx *transaction.Transaction, inputIndex uint32) uint32 {
+	m := len(p.PrivateKeys)
+	if tx != nil && int(inputIndex) < len(tx.Inputs) {
+		if _, threshold, err := Decode(tx.Inputs[inputIndex].SourceTxScript()); err == nil {
+			m = threshold
+		}
+	}
+	return 1 + uint32(m)*transaction.MaxSignaturePushLength
+}
+
+// UnlockWithSignatures creates an unlocking template from signatures
+// gathered from the cosigners, for example with MultiSig.Signatures, each
+// with its sighash flag appended. The signatures must be ordered by the
+// position of their public key in the locking script, as OP_CHECKMULTISIG
+// requires, and there must be exactly as many as the threshold.
+func UnlockWithSignatures(sigs [][]byte) (*CombinedMultiSig, error) {
+	if len(sigs) == 0 {
+		return nil, ErrNoSignatures
+	}
+	for _, sig := range sigs {
+		if len(sig) < 2 {
+			return nil, ErrNoSignatures
+		}
+	}
+	return &CombinedMultiSig{Signatures: sigs}, nil
+}
+
+type CombinedMultiSig struct {
+	Signatures [][]byte
+}
+
+// Sign checks the signatures against the public keys of the locking script
+// being spent and produces the unlocking script OP_0 <sig1> ... <sigm>.
+func (c *CombinedMultiSig) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	if tx.Inputs[inputIndex].SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+	pubKeys, m, err := Decode(tx.Inputs[inputIndex].SourceTxScript())
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Signatures) < m {
+		return nil, ErrInsufficientSignature
+	}
+	if len(c.Signatures) > m {
+		return nil, ErrTooManySignatures
+	}
+
+	// Match signatures to keys the way OP_CHECKMULTISIG does, each
+	// signature against the keys following the previous match.
+	k := 0
+	for _, sig := range c.Signatures {
+		for ; k < len(pubKeys); k++ {
+			if verifies(tx, inputIndex, sig, pubKeys[k]) {
+				break
+			}
+		}
+		if k == len(pubKeys) {
+			return nil, ErrBadSignatureOrder
+		}
+		k++
+	}
+
+	s := &script.Script{}
+	_ = s.AppendOpcodes(script.Op0)
+	for _, sig := range c.Signatures {
+		if err = s.AppendPushData(sig); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// EstimateLength returns the exact length of the unlocking script.
+func (c *CombinedMultiSig) EstimateLength(_ *transaction.Transaction, _ uint32) uint32 {
+	length := uint32(1)
+	for _, sig := range c.Signatures {
+		length += 1 + uint32(len(sig))
+	}
+	return length
+}
+
+// verifies reports whether sig, with its sighash flag appended, is a valid
+// signature of the input by pubKey.
+func verifies(tx *transaction.Transaction, inputIndex uint32, sig []byte, pubKey *ec.PublicKey) bool {
+	flag := sighash.Flag(sig[len(sig)-1])
+	parsed, err := ec.ParseDERSignature(sig[:len(sig)-1])
+	if err != nil {
+		return false
+	}
+	sh, err := tx.CalcInputSignatureHash(inputIndex, flag)
+	if err != nil {
+		return false
+	}
+	return parsed.Verify(sh, pubKey)
+}
+
+func (p *MultiSig) keyFor(pk *ec.PublicKey) *ec.PrivateKey {
+	compressed := pk.Compressed()
+	for _, key := range p.PrivateKeys {
+		if bytes.Equal(key.PubKey().Compressed(), compressed) {
+			return key
+		}
+	}
+	return nil
+}
+
+func smallInt(op byte) int {
+	if op == script.Op0 {
+		return 0
+	}
+	return int(op-script.Op1) + 1
+}
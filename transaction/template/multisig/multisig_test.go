@@ -0,0 +1,159 @@
+package multisig_test
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/multisig"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeys(t *testing.T, n int) ([]*ec.PrivateKey, []*ec.PublicKey) {
+	privs := make([]*ec.PrivateKey, n)
+	pubs := make([]*ec.PublicKey, n)
+	for i := range privs {
+		priv, err := ec.NewPrivateKey()
+		require.NoError(t, err)
+		privs[i] = priv
+		pubs[i] = priv.PubKey()
+	}
+	return privs, pubs
+}
+
+func spendingTx(t *testing.T, lockingScript *script.Script, unlocker transaction.UnlockingScriptTemplate) *transaction.Transaction {
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+		0,
+		lockingScript.String(),
+		10000,
+		unlocker,
+	))
+	require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 9000))
+	return tx
+}
+
+func TestLock(t *testing.T) {
+	_, pubs := newKeys(t, 3)
+
+	s, err := multisig.Lock(pubs, 2)
+	require.NoError(t, err)
+	require.True(t, s.IsMultiSigOut())
+
+	decoded, m, err := multisig.Decode(s)
+	require.NoError(t, err)
+	require.Equal(t, 2, m)
+	require.Len(t, decoded, 3)
+	for i := range pubs {
+		require.True(t, pubs[i].IsEqual(decoded[i]))
+	}
+
+	_, err = multisig.Lock(pubs, 0)
+	require.ErrorIs(t, err, multisig.ErrBadThreshold)
+	_, err = multisig.Lock(pubs, 4)
+	require.ErrorIs(t, err, multisig.ErrBadThreshold)
+	_, err = multisig.Lock(nil, 1)
+	require.ErrorIs(t, err, multisig.ErrNoPublicKeys)
+}
+
+func TestSign(t *testing.T) {
+	privs, pubs := newKeys(t, 3)
+	lockingScript, err := multisig.Lock(pubs, 2)
+	require.NoError(t, err)
+
+	t.Run("keys out of order", func(t *testing.T) {
+		unlocker, err := multisig.Unlock([]*ec.PrivateKey{privs[2], privs[0]}, nil)
+		require.NoError(t, err)
+
+		tx := spendingTx(t, lockingScript, unlocker)
+		require.NoError(t, tx.Sign())
+
+		unlockingScript := tx.Inputs[0].UnlockingScript
+		parts, err := unlockingScript.Chunks()
+		require.NoError(t, err)
+		require.Len(t, parts, 3)
+		require.Equal(t, script.Op0, parts[0].Op)
+		require.LessOrEqual(t, uint32(len(*unlockingScript)), unlocker.EstimateLength(tx, 0))
+
+		require.NoError(t, interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		))
+	})
+
+	t.Run("partial signatures", func(t *testing.T) {
+		unlocker, err := multisig.Unlock([]*ec.PrivateKey{privs[1]}, nil)
+		require.NoError(t, err)
+
+		tx := spendingTx(t, lockingScript, unlocker)
+		sigs, m, err := unlocker.Signatures(tx, 0)
+		require.NoError(t, err)
+		require.Equal(t, 2, m)
+		require.Len(t, sigs, 1)
+
+		_, err = unlocker.Sign(tx, 0)
+		require.ErrorIs(t, err, multisig.ErrInsufficientSignature)
+	})
+}
+
+func TestEstimateLength(t *testing.T) {
+	privs, pubs := newKeys(t, 3)
+	lockingScript, err := multisig.Lock(pubs, 2)
+	require.NoError(t, err)
+
+	unlocker, err := multisig.Unlock(privs, nil)
+	require.NoError(t, err)
+
+	tx := spendingTx(t, lockingScript, unlocker)
+	require.Equal(t, uint32(1+2*73), unlocker.EstimateLength(tx, 0))
+}
+
+func TestUnlockWithSignatures(t *testing.T) {
+	privs, pubs := newKeys(t, 3)
+	lockingScript, err := multisig.Lock(pubs, 2)
+	require.NoError(t, err)
+
+	// Each cosigner signs separately with their own key.
+	signer0, err := multisig.Unlock([]*ec.PrivateKey{privs[0]}, nil)
+	require.NoError(t, err)
+	signer2, err := multisig.Unlock([]*ec.PrivateKey{privs[2]}, nil)
+	require.NoError(t, err)
+	tx := spendingTx(t, lockingScript, signer0)
+	sigs0, _, err := signer0.Signatures(tx, 0)
+	require.NoError(t, err)
+	sigs2, _, err := signer2.Signatures(tx, 0)
+	require.NoError(t, err)
+
+	combined, err := multisig.UnlockWithSignatures([][]byte{sigs0[0], sigs2[0]})
+	require.NoError(t, err)
+	tx.Inputs[0].UnlockingScriptTemplate = combined
+	require.NoError(t, tx.Sign())
+	require.Equal(t, uint32(len(*tx.Inputs[0].UnlockingScript)), combined.EstimateLength(tx, 0))
+	require.NoError(t, interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	))
+
+	reversed, err := multisig.UnlockWithSignatures([][]byte{sigs2[0], sigs0[0]})
+	require.NoError(t, err)
+	_, err = reversed.Sign(tx, 0)
+	require.ErrorIs(t, err, multisig.ErrBadSignatureOrder)
+
+	one, err := multisig.UnlockWithSignatures([][]byte{sigs0[0]})
+	require.NoError(t, err)
+	_, err = one.Sign(tx, 0)
+	require.ErrorIs(t, err, multisig.ErrInsufficientSignature)
+
+	three, err := multisig.UnlockWithSignatures([][]byte{sigs0[0], sigs2[0], sigs2[0]})
+	require.NoError(t, err)
+	_, err = three.Sign(tx, 0)
+	require.ErrorIs(t, err, multisig.ErrTooManySignatures)
+
+	_, err = multisig.UnlockWithSignatures(nil)
+	require.ErrorIs(t, err, multisig.ErrNoSignatures)
+}
@@ -0,0 +1,263 @@
+// Package ordlock provides a template for listing an ordinal for sale, as
+// done by 1Sat ordinal marketplaces. An OrdLock output holds the ordinal and
+// can be spent in one of two ways:
+//
+//   - cancelled by the seller, with a signature from the listing key
+//   - purchased by anyone, with a transaction whose second output pays the
+//     seller the asking price
+//
+// The locking script is the OrdLock contract used by the js-1sat-ord
+// library and the 1Sat marketplaces and indexers:
+//
+//	<prefix> <seller pkh> <payment output> <suffix>
+//
+// The purchase path checks the outputs of the spending transaction using
+// the OP_PUSH_TX technique: the unlocking script pushes the sighash
+// preimage, which the contract signs with a known key to prove that it
+// belongs to the transaction, before comparing its hashOutputs field to the
+// expected outputs. The first output receives the ordinal, so the OrdLock
+// input should be the first input of the purchase.
+package ordlock
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+var (
+	ErrBadPublicKeyHash = errors.New("invalid public key hash")
+	ErrNoPayment        = errors.New("payment output not supplied")
+	ErrNoPrivateKey     = errors.New("private key not supplied")
+	ErrNotOrdLock       = errors.New("script is not an ordlock output")
+	ErrKeyMismatch      = errors.New("private key does not match the seller key")
+	ErrNoBuyerOutput    = errors.New("purchase has no output to receive the ordinal")
+	ErrPaymentMismatch  = errors.New("second output of the purchase does not pay the listing")
+)
+
+// PurchaseSigHashFlag is the sighash flag of the preimage the contract
+// checks when the listing is purchased.
+const PurchaseSigHashFlag = sighash.AllForkID | sighash.AnyOneCanPay
+
+var (
+	// Prefix is the part of an OrdLock script before the seller's public
+	// key hash.
+	Prefix, _ = hex.DecodeString("2097dfd76851bf465e8f715593b217714858bbe9570ff3bd5e33840a34e20ff0262102ba79df5f8ae7604a9830f03c7933028186aede0675a16f025dc4f8be8eec0382201008ce7480da41702918d1ec8e6849ba32b4d65b1e40dc669c31a1e6306b266c0000")
+	// Suffix is the part of an OrdLock script after the payment output.
+	Suffix, _ = hex.DecodeString("615179547a75537a537a537a0079537a75527a527a7575615579008763567901c161517957795779210ac407f0e4bd44bfc207355a778b046225a7068fc59ee7eda43ad905aadbffc800206c266b30e6a1319c66dc401e5bd6b432ba49688eecd118297041da8074ce081059795679615679aa0079610079517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e01007e81517a75615779567956795679567961537956795479577995939521414136d08c5ed2bf3ba048afe6dcaebafeffffffffffffffffffffffffffffff00517951796151795179970079009f63007952799367007968517a75517a75517a7561527a75517a517951795296a0630079527994527a75517a6853798277527982775379012080517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f517f7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e7c7e01205279947f7754537993527993013051797e527e54797e58797e527e53797e52797e57797e0079517a75517a75517a75517a75517a75517a75517a75517a75517a75517a75517a75517a75517a756100795779ac517a75517a75517a75517a75517a75517a75517a75517a75517a7561517a75517a756169587951797e58797eaa577961007982775179517958947f7551790128947f77517a75517a75618777777777777777777767557951876351795779a9876957795779ac777777777777777767006868")
+)
+
+// Lock creates a locking script listing the ordinal for sale. The output
+// can be cancelled by the owner of seller, or purchased by a transaction
+// whose second output is exactly payment.
+func Lock(seller *script.Address, payment *transaction.TransactionOutput) (*script.Script, error) {
+	if len(seller.PublicKeyHash) != 20 {
+		return nil, ErrBadPublicKeyHash
+	}
+	if payment == nil || payment.LockingScript == nil {
+		return nil, ErrNoPayment
+	}
+	s := script.NewFromBytes(bytes.Clone(Prefix))
+	if err := s.AppendPushData(seller.PublicKeyHash); err != nil {
+		return nil, err
+	}
+	if err := s.AppendPushData(payment.Bytes()); err != nil {
+		return nil, err
+	}
+	*s = append(*s, Suffix...)
+	return s, nil
+}
+
+// IsOrdLock reports whether the script contains the OrdLock prefix and
+// suffix, without decoding it.
+func IsOrdLock(s *script.Script) bool {
+	if s == nil {
+		return false
+	}
+	i := bytes.Index(*s, Prefix)
+	return i >= 0 && bytes.Contains((*s)[i+len(Prefix):], Suffix)
+}
+
+// Decode returns the seller's public key hash and the payment output of an
+// OrdLock locking script. The contract may be preceded by other script,
+// such as an inscription envelope, as is common for marketplace listings.
+func Decode(s *script.Script) ([]byte, *transaction.TransactionOutput, error) {
+	if s == nil {
+		return nil, nil, ErrNotOrdLock
+	}
+	i := bytes.Index(*s, Prefix)
+	if i < 0 {
+		return nil, nil, ErrNotOrdLock
+	}
+	b := []byte(*s)[i+len(Prefix):]
+	j := bytes.Index(b, Suffix)
+	if j < 0 {
+		return nil, nil, ErrNotOrdLock
+	}
+	// Between the prefix and suffix are exactly two pushes.
+	chunks, err := script.DecodeScript(b[:j])
+	if err != nil || len(chunks) != 2 || chunks[0].Op > script.OpPUSHDATA4 ||
+		chunks[1].Op > script.OpPUSHDATA4 || len(chunks[0].Data) != 20 {
+		return nil, nil, ErrNotOrdLock
+	}
+	pkh, payment := chunks[0].Data, chunks[1].Data
+
+	output := &transaction.TransactionOutput{}
+	if n, err := output.ReadFrom(bytes.NewReader(payment)); err != nil || int(n) != len(payment) {
+		return nil, nil, ErrNotOrdLock
+	}
+	return pkh, output, nil
+}
+
+// Cancel returns an unlocker which cancels the listing with the seller's key.
+func Cancel(key *ec.PrivateKey, sigHashFlag *sighash.Flag) (*OrdLockCancel, error) {
+	if key == nil {
+		return nil, ErrNoPrivateKey
+	}
+	if sigHashFlag == nil {
+		shf := sighash.AllForkID
+		sigHashFlag = &shf
+	}
+	return &OrdLockCancel{
+		PrivateKey:  key,
+		SigHashFlag: sigHashFlag,
+	}, nil
+}
+
+type OrdLockCancel struct {
+	PrivateKey  *ec.PrivateKey
+	SigHashFlag *sighash.Flag
+}
+
+func (c *OrdLockCancel) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	if tx.Inputs[inputIndex].SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+
+	pkh, _, err := Decode(tx.Inputs[inputIndex].SourceTxScript())
+	if err != nil {
+		return nil, err
+	}
+	pubKey := c.PrivateKey.PubKey().Compressed()
+	if !bytes.Equal(pkh, crypto.Hash160(pubKey)) {
+		return nil, ErrKeyMismatch
+	}
+
+	sh, err := tx.CalcInputSignatureHash(inputIndex, *c.SigHashFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := c.PrivateKey.Sign(sh)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBuf := make([]byte, 0)
+	sigBuf = append(sigBuf, sig.Serialize()...)
+	sigBuf = append(sigBuf, uint8(*c.SigHashFlag))
+
+	s := &script.Script{}
+	if err = s.AppendPushData(sigBuf); err != nil {
+		return nil, err
+	} else if err = s.AppendPushData(pubKey); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(script.Op1)
+
+	return s, nil
+}
+
+// EstimateLength returns the worst case length of the unlocking script: that
+// of a P2PKH unlocking script followed by OP_1 to select the cancel path.
+func (c *OrdLockCancel) EstimateLength(_ *transaction.Transaction, inputIndex uint32) uint32 {
+	return transaction.P2PKHUnlockingScriptLength + 1
+}
+
+// Purchase returns an unlocker which buys the listed ordinal. The first
+// output of the transaction receives the ordinal and the second must be the
+// payment output of the listing.
+func Purchase() (*OrdLockPurchase, error) {
+	return &OrdLockPurchase{}, nil
+}
+
+// OrdLockPurchase unlocks an OrdLock output by purchasing it. The unlocking
+// script needs no signature, so the buyer's other inputs pay for the
+// purchase.
+type OrdLockPurchase struct{}
+
+// Sign produces the unlocking script
+// <buyer output> <other outputs> <preimage> OP_0, where the other outputs
+// are the outputs after the payment, concatenated.
+func (p *OrdLockPurchase) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	in := tx.Inputs[inputIndex]
+	if in.SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+	_, payment, err := Decode(in.SourceTxScript())
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.Outputs) < 2 {
+		return nil, ErrNoBuyerOutput
+	}
+	if !bytes.Equal(tx.Outputs[1].Bytes(), payment.Bytes()) {
+		return nil, ErrPaymentMismatch
+	}
+
+	preimage, err := tx.CalcInputPreimage(inputIndex, PurchaseSigHashFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	others := make([]byte, 0)
+	for _, o := range tx.Outputs[2:] {
+		others = append(others, o.Bytes()...)
+	}
+
+	s := &script.Script{}
+	if err = s.AppendPushData(tx.Outputs[0].Bytes()); err != nil {
+		return nil, err
+	} else if err = s.AppendPushData(others); err != nil {
+		return nil, err
+	} else if err = s.AppendPushData(preimage); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(script.Op0)
+
+	return s, nil
+}
+
+// EstimateLength measures the pushes of the transaction's outputs and of the
+// preimage over the OrdLock script. If the source output is not attached
+// yet, the listing is assumed to pay a P2PKH output and to have no
+// inscription, and a missing buyer output is assumed to be P2PKH.
+func (p *OrdLockPurchase) EstimateLength(tx *transaction.Transaction, inputIndex uint32) uint32 {
+	scriptLen := len(Prefix) + 21 + 1 + 34 + len(Suffix)
+	buyerLen, othersLen := 34, 0
+	if tx != nil {
+		if int(inputIndex) < len(tx.Inputs) {
+			if s := tx.Inputs[inputIndex].SourceTxScript(); s != nil {
+				scriptLen = len(*s)
+			}
+		}
+		if len(tx.Outputs) > 0 {
+			buyerLen = len(tx.Outputs[0].Bytes())
+		}
+		for i := 2; i < len(tx.Outputs); i++ {
+			othersLen += len(tx.Outputs[i].Bytes())
+		}
+	}
+	preimageLen := 4 + 32 + 32 + 36 + transaction.VarInt(scriptLen).Length() + scriptLen + 8 + 4 + 32 + 4 + 4
+	pushLen := func(n int) int {
+		prefix, _ := script.PushDataPrefix(make([]byte, n))
+		return len(prefix) + n
+	}
+	return uint32(pushLen(buyerLen) + pushLen(othersLen) + pushLen(preimageLen) + 1)
+}
@@ -0,0 +1,203 @@
+package ordlock_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter/scriptflag"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/ordlock"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/require"
+)
+
+type party struct {
+	key     *ec.PrivateKey
+	address *script.Address
+	lock    *script.Script
+}
+
+func newParty(t *testing.T) *party {
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(key.PubKey(), true)
+	require.NoError(t, err)
+	lock, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	return &party{key: key, address: address, lock: lock}
+}
+
+const sourceTxID = "45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d"
+
+func newListing(t *testing.T, seller *party) (*script.Script, *transaction.TransactionOutput) {
+	payment := &transaction.TransactionOutput{Satoshis: 5000, LockingScript: seller.lock}
+	listing, err := ordlock.Lock(seller.address, payment)
+	require.NoError(t, err)
+
+	pkh, decoded, err := ordlock.Decode(listing)
+	require.NoError(t, err)
+	require.Equal(t, []byte(seller.address.PublicKeyHash), pkh)
+	require.Equal(t, payment.Bytes(), decoded.Bytes())
+	return listing, payment
+}
+
+func requireValid(t *testing.T, tx *transaction.Transaction) {
+	for i := range tx.Inputs {
+		require.NoError(t, interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, i, tx.Inputs[i].SourceTxOutput()),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+			// Node policy requires low-S, strictly encoded signatures, which
+			// the contract's own signature over the preimage must satisfy.
+			interpreter.WithFlags(scriptflag.VerifyLowS|scriptflag.VerifyStrictEncoding|scriptflag.VerifyDERSignatures),
+		))
+	}
+}
+
+func TestPurchase(t *testing.T) {
+	seller, buyer := newParty(t), newParty(t)
+	listing, payment := newListing(t, seller)
+
+	buyerUnlocker, err := p2pkh.Unlock(buyer.key, nil)
+	require.NoError(t, err)
+
+	// Purchase several times to cover preimages whose signature by the
+	// contract has a high S value.
+	for i := 0; i < 20; i++ {
+		purchase, err := ordlock.Purchase()
+		require.NoError(t, err)
+
+		tx := transaction.NewTransaction()
+		require.NoError(t, tx.AddInputFrom(sourceTxID, uint32(i), listing.String(), 1, purchase))
+		require.NoError(t, tx.AddInputFrom(sourceTxID, 100, buyer.lock.String(), 10000, buyerUnlocker))
+		tx.AddOutput(&transaction.TransactionOutput{Satoshis: 1, LockingScript: buyer.lock})
+		tx.AddOutput(payment)
+		tx.AddOutput(&transaction.TransactionOutput{Satoshis: 4900, LockingScript: buyer.lock})
+		require.NoError(t, tx.Sign())
+		requireValid(t, tx)
+		require.Equal(t, len(*tx.Inputs[0].UnlockingScript), int(purchase.EstimateLength(tx, 0)))
+
+		// The listing cannot be bought without paying the seller.
+		tx.Outputs[1] = &transaction.TransactionOutput{Satoshis: 5000, LockingScript: buyer.lock}
+		_, err = purchase.Sign(tx, 0)
+		require.ErrorIs(t, err, ordlock.ErrPaymentMismatch)
+	}
+}
+
+func TestPurchaseRejectsTamperedOutputs(t *testing.T) {
+	seller, buyer := newParty(t), newParty(t)
+	listing, payment := newListing(t, seller)
+
+	purchase, err := ordlock.Purchase()
+	require.NoError(t, err)
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(sourceTxID, 0, listing.String(), 1, purchase))
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: 1, LockingScript: buyer.lock})
+	tx.AddOutput(payment)
+	require.NoError(t, tx.Sign())
+
+	// Redirecting the payment after signing breaks the preimage check.
+	tx.Outputs[1] = &transaction.TransactionOutput{Satoshis: 5000, LockingScript: buyer.lock}
+	require.Error(t, interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	))
+}
+
+func TestCancel(t *testing.T) {
+	seller, other := newParty(t), newParty(t)
+	listing, _ := newListing(t, seller)
+
+	cancel, err := ordlock.Cancel(seller.key, nil)
+	require.NoError(t, err)
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(sourceTxID, 0, listing.String(), 1, cancel))
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: 1, LockingScript: seller.lock})
+	require.NoError(t, tx.Sign())
+	requireValid(t, tx)
+	require.LessOrEqual(t, len(*tx.Inputs[0].UnlockingScript), int(cancel.EstimateLength(tx, 0)))
+
+	wrong, err := ordlock.Cancel(other.key, nil)
+	require.NoError(t, err)
+	_, err = wrong.Sign(tx, 0)
+	require.ErrorIs(t, err, ordlock.ErrKeyMismatch)
+
+	_, _, err = ordlock.Decode(seller.lock)
+	require.ErrorIs(t, err, ordlock.ErrNotOrdLock)
+}
+
+func TestEstimateLengthWithoutSourceOutput(t *testing.T) {
+	seller, buyer := newParty(t), newParty(t)
+	listing, payment := newListing(t, seller)
+
+	purchase, err := ordlock.Purchase()
+	require.NoError(t, err)
+	cancel, err := ordlock.Cancel(seller.key, nil)
+	require.NoError(t, err)
+	txid, err := chainhash.NewHashFromHex(sourceTxID)
+	require.NoError(t, err)
+
+	tx := transaction.NewTransaction()
+	tx.AddInput(&transaction.TransactionInput{SourceTXID: txid, UnlockingScriptTemplate: purchase})
+	tx.AddInput(&transaction.TransactionInput{SourceTXID: txid, SourceTxOutIndex: 1, UnlockingScriptTemplate: cancel})
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: 1, LockingScript: buyer.lock})
+	tx.AddOutput(payment)
+	tx.AddOutput(&transaction.TransactionOutput{Satoshis: 4900, LockingScript: buyer.lock})
+	require.Equal(t, uint32(108), cancel.EstimateLength(tx, 1))
+
+	// Without the source output a listing paying a P2PKH output is assumed,
+	// which this one is.
+	estimate := purchase.EstimateLength(tx, 0)
+	_, err = tx.EstimateSize()
+	require.NoError(t, err)
+
+	tx.Inputs[0].SetSourceTxOutput(&transaction.TransactionOutput{Satoshis: 1, LockingScript: listing})
+	require.Equal(t, estimate, purchase.EstimateLength(tx, 0))
+	unlockingScript, err := purchase.Sign(tx, 0)
+	require.NoError(t, err)
+	require.Equal(t, len(*unlockingScript), int(estimate))
+}
+
+func TestDecodeInscribedListing(t *testing.T) {
+	// A marketplace listing: an inscription envelope followed by the OrdLock
+	// contract, selling for 5000 satoshis to the seller's P2PKH address.
+	envelope, err := script.NewFromHex("0063036f726451126170706c69636174696f6e2f6a736f6e00027b7d68")
+	require.NoError(t, err)
+	sellerPKH := "1ed1b4bc0a59c1ae6ba1bbc5a25cfcbb16e9d8a3"
+	paymentHex := "8813000000000000" + "1976a914" + sellerPKH + "88ac"
+
+	s := script.NewFromBytes(append(append(append([]byte{}, *envelope...), ordlock.Prefix...), 0x14))
+	*s = append(*s, mustHex(t, sellerPKH)...)
+	*s = append(*s, 0x22)
+	*s = append(*s, mustHex(t, paymentHex)...)
+	*s = append(*s, ordlock.Suffix...)
+	require.True(t, ordlock.IsOrdLock(s))
+
+	pkh, payment, err := ordlock.Decode(s)
+	require.NoError(t, err)
+	require.Equal(t, sellerPKH, hex.EncodeToString(pkh))
+	require.Equal(t, uint64(5000), payment.Satoshis)
+	require.Equal(t, paymentHex, hex.EncodeToString(payment.Bytes()))
+
+	// Lock produces the same contract for the same listing.
+	address, err := script.NewAddressFromPublicKeyHash(pkh, true)
+	require.NoError(t, err)
+	lock, err := ordlock.Lock(address, payment)
+	require.NoError(t, err)
+	require.Equal(t, []byte(*s)[len(*envelope):], []byte(*lock))
+
+	require.False(t, ordlock.IsOrdLock(envelope))
+	_, _, err = ordlock.Decode(script.NewFromBytes(ordlock.Prefix))
+	require.ErrorIs(t, err, ordlock.ErrNotOrdLock)
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,81 @@
+package p2pk
+
+import (
+	"errors"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+var (
+	ErrNoPublicKey  = errors.New("public key not supplied")
+	ErrNoPrivateKey = errors.New("private key not supplied")
+)
+
+// Lock creates a P2PK locking script of the form <pubkey> OP_CHECKSIG
+// using the compressed encoding of the public key.
+func Lock(pubKey *ec.PublicKey) (*script.Script, error) {
+	if pubKey == nil {
+		return nil, ErrNoPublicKey
+	}
+	s := &script.Script{}
+	if err := s.AppendPushData(pubKey.Compressed()); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(script.OpCHECKSIG)
+	return s, nil
+}
+
+func Unlock(key *ec.PrivateKey, sigHashFlag *sighash.Flag) (*P2PK, error) {
+	if key == nil {
+		return nil, ErrNoPrivateKey
+	}
+	if sigHashFlag == nil {
+		shf := sighash.AllForkID
+		sigHashFlag = &shf
+	}
+	return &P2PK{
+		PrivateKey:  key,
+		SigHashFlag: sigHashFlag,
+	}, nil
+}
+
+type P2PK struct {
+	PrivateKey  *ec.PrivateKey
+	SigHashFlag *sighash.Flag
+}
+
+func (p *P2PK) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	if tx.Inputs[inputIndex].SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+
+	sh, err := tx.CalcInputSignatureHash(inputIndex, *p.SigHashFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := p.PrivateKey.Sign(sh)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBuf := make([]byte, 0)
+	sigBuf = append(sigBuf, sig.Serialize()...)
+	sigBuf = append(sigBuf, uint8(*p.SigHashFlag))
+
+	s := &script.Script{}
+	if err = s.AppendPushData(sigBuf); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// EstimateLength returns the worst case length of the unlocking script, a
+// single signature push.
+func (p *P2PK) EstimateLength(_ *transaction.Transaction, inputIndex uint32) uint32 {
+	return transaction.MaxSignaturePushLength
+}
@@ -0,0 +1,62 @@
+package p2pk_test
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	priv, err := ec.PrivateKeyFromWif("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	require.NoError(t, err)
+
+	s, err := p2pk.Lock(priv.PubKey())
+	require.NoError(t, err)
+	require.True(t, s.IsP2PK())
+
+	pubKey, err := s.PubKey()
+	require.NoError(t, err)
+	require.True(t, pubKey.IsEqual(priv.PubKey()))
+
+	_, err = p2pk.Lock(nil)
+	require.ErrorIs(t, err, p2pk.ErrNoPublicKey)
+}
+
+func TestSign(t *testing.T) {
+	priv, err := ec.PrivateKeyFromWif("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	require.NoError(t, err)
+
+	lockingScript, err := p2pk.Lock(priv.PubKey())
+	require.NoError(t, err)
+
+	unlocker, err := p2pk.Unlock(priv, nil)
+	require.NoError(t, err)
+
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+		0,
+		lockingScript.String(),
+		5000000000,
+		unlocker,
+	))
+	require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 4999999000))
+	require.NoError(t, tx.Sign())
+
+	unlockingScript := tx.Inputs[0].UnlockingScript
+	parts, err := script.DecodeScript(*unlockingScript)
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	require.LessOrEqual(t, uint32(len(*unlockingScript)), unlocker.EstimateLength(tx, 0))
+
+	require.NoError(t, interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	))
+}
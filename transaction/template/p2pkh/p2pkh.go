@@ -78,6 +78,8 @@ func (p *P2PKH) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Sc
 	return s, nil
 }
 
+// EstimateLength returns the worst case length of the unlocking script,
+// transaction.P2PKHUnlockingScriptLength.
 func (p *P2PKH) EstimateLength(_ *transaction.Transaction, inputIndex uint32) uint32 {
-	return 106
+	return transaction.P2PKHUnlockingScriptLength
 }
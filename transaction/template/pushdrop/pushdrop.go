@@ -0,0 +1,283 @@
+package pushdrop
+
+import (
+	"bytes"
+	"errors"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+var (
+	ErrNoPublicKey      = errors.New("public key not supplied")
+	ErrNoFields         = errors.New("at least one field must be supplied")
+	ErrBadPublicKeyHash = errors.New("invalid public key hash")
+	ErrNoPrivateKey     = errors.New("private key not supplied")
+	ErrNotPushDrop      = errors.New("script is not a pushdrop output")
+	ErrKeyMismatch      = errors.New("private key does not match the locking key")
+)
+
+// LockPosition determines where the key check is placed
+// relative to the data fields in the locking script.
+type LockPosition int
+
+const (
+	// LockAfter places the key check after the fields and drops:
+	// <field1> ... <fieldN> OP_2DROP ... [OP_DROP] <check>
+	LockAfter LockPosition = iota
+	// LockBefore places the key check before the fields and drops:
+	// <check> <field1> ... <fieldN> OP_2DROP ... [OP_DROP]
+	LockBefore
+)
+
+// Decoded holds the contents of a pushdrop locking script. Exactly one of
+// PublicKey and PublicKeyHash is set, depending on the style of key check.
+type Decoded struct {
+	PublicKey     *ec.PublicKey
+	PublicKeyHash []byte
+	Fields        [][]byte
+	LockPosition  LockPosition
+}
+
+// Lock creates a pushdrop locking script which carries the fields and is
+// spendable by a signature from pubKey (a P2PK-style check).
+func Lock(pubKey *ec.PublicKey, fields [][]byte, position LockPosition) (*script.Script, error) {
+	if pubKey == nil {
+		return nil, ErrNoPublicKey
+	}
+	check := &script.Script{}
+	if err := check.AppendPushData(pubKey.Compressed()); err != nil {
+		return nil, err
+	}
+	_ = check.AppendOpcodes(script.OpCHECKSIG)
+	return build(check, fields, position)
+}
+
+// LockPKH creates a pushdrop locking script which carries the fields and is
+// spendable by the owner of the address (a P2PKH-style check).
+func LockPKH(a *script.Address, fields [][]byte, position LockPosition) (*script.Script, error) {
+	if len(a.PublicKeyHash) != 20 {
+		return nil, ErrBadPublicKeyHash
+	}
+	b := make([]byte, 0, 25)
+	b = append(b, script.OpDUP, script.OpHASH160, script.OpDATA20)
+	b = append(b, a.PublicKeyHash...)
+	b = append(b, script.OpEQUALVERIFY, script.OpCHECKSIG)
+	check := script.Script(b)
+	return build(&check, fields, position)
+}
+
+func build(check *script.Script, fields [][]byte, position LockPosition) (*script.Script, error) {
+	if len(fields) == 0 {
+		return nil, ErrNoFields
+	}
+	s := &script.Script{}
+	if position == LockBefore {
+		*s = append(*s, *check...)
+	}
+	for _, field := range fields {
+		if err := appendMinimalPush(s, field); err != nil {
+			return nil, err
+		}
+	}
+	for remaining := len(fields); remaining > 0; remaining -= 2 {
+		if remaining == 1 {
+			_ = s.AppendOpcodes(script.OpDROP)
+		} else {
+			_ = s.AppendOpcodes(script.Op2DROP)
+		}
+	}
+	if position == LockAfter {
+		*s = append(*s, *check...)
+	}
+	return s, nil
+}
+
+// appendMinimalPush pushes the field using the smallest encoding,
+// so that the locking script complies with the minimal data rules.
+func appendMinimalPush(s *script.Script, field []byte) error {
+	switch {
+	case len(field) == 0:
+		return s.AppendOpcodes(script.Op0)
+	case len(field) == 1 && field[0] >= 1 && field[0] <= 16:
+		return s.AppendOpcodes(script.Op1 + field[0] - 1)
+	case len(field) == 1 && field[0] == 0x81:
+		return s.AppendOpcodes(script.Op1NEGATE)
+	}
+	return s.AppendPushData(field)
+}
+
+// Decode extracts the fields and the locking key from a pushdrop script.
+func Decode(s *script.Script) (*Decoded, error) {
+	if s == nil {
+		return nil, ErrNotPushDrop
+	}
+	chunks, err := s.Chunks()
+	if err != nil {
+		return nil, ErrNotPushDrop
+	}
+
+	d := &Decoded{}
+	switch {
+	case decodeCheck(d, chunks):
+		d.LockPosition = LockBefore
+		if len(d.PublicKeyHash) > 0 {
+			chunks = chunks[5:]
+		} else {
+			chunks = chunks[2:]
+		}
+	case len(chunks) >= 5 && decodeCheck(d, chunks[len(chunks)-5:]) && len(d.PublicKeyHash) > 0:
+		d.LockPosition = LockAfter
+		chunks = chunks[:len(chunks)-5]
+	case len(chunks) >= 2 && decodeCheck(d, chunks[len(chunks)-2:]):
+		d.LockPosition = LockAfter
+		chunks = chunks[:len(chunks)-2]
+	default:
+		return nil, ErrNotPushDrop
+	}
+
+	dropped := 0
+	end := len(chunks)
+	for end > 0 {
+		if op := chunks[end-1].Op; op == script.OpDROP {
+			dropped++
+		} else if op == script.Op2DROP {
+			dropped += 2
+		} else {
+			break
+		}
+		end--
+	}
+	if dropped == 0 || dropped != end {
+		return nil, ErrNotPushDrop
+	}
+
+	d.Fields = make([][]byte, 0, end)
+	for _, chunk := range chunks[:end] {
+		field, ok := pushedData(chunk)
+		if !ok {
+			return nil, ErrNotPushDrop
+		}
+		d.Fields = append(d.Fields, field)
+	}
+
+	return d, nil
+}
+
+// decodeCheck reports whether the chunks start with a P2PK or P2PKH style
+// key check, recording the key in d if so.
+func decodeCheck(d *Decoded, chunks []*script.ScriptChunk) bool {
+	if len(chunks) >= 5 &&
+		chunks[0].Op == script.OpDUP &&
+		chunks[1].Op == script.OpHASH160 &&
+		len(chunks[2].Data) == 20 &&
+		chunks[3].Op == script.OpEQUALVERIFY &&
+		chunks[4].Op == script.OpCHECKSIG {
+		d.PublicKey = nil
+		d.PublicKeyHash = chunks[2].Data
+		return true
+	}
+	if len(chunks) >= 2 && chunks[1].Op == script.OpCHECKSIG && len(chunks[0].Data) > 0 {
+		pubKey, err := ec.ParsePubKey(chunks[0].Data)
+		if err != nil {
+			return false
+		}
+		d.PublicKey = pubKey
+		d.PublicKeyHash = nil
+		return true
+	}
+	return false
+}
+
+func pushedData(chunk *script.ScriptChunk) ([]byte, bool) {
+	switch {
+	case chunk.Op == script.Op0:
+		return []byte{}, true
+	case chunk.Op >= script.Op1 && chunk.Op <= script.Op16:
+		return []byte{chunk.Op - script.Op1 + 1}, true
+	case chunk.Op == script.Op1NEGATE:
+		return []byte{0x81}, true
+	case chunk.Op <= script.OpPUSHDATA4:
+		return chunk.Data, true
+	}
+	return nil, false
+}
+
+func Unlock(key *ec.PrivateKey, sigHashFlag *sighash.Flag) (*PushDrop, error) {
+	if key == nil {
+		return nil, ErrNoPrivateKey
+	}
+	if sigHashFlag == nil {
+		shf := sighash.AllForkID
+		sigHashFlag = &shf
+	}
+	return &PushDrop{
+		PrivateKey:  key,
+		SigHashFlag: sigHashFlag,
+	}, nil
+}
+
+type PushDrop struct {
+	PrivateKey  *ec.PrivateKey
+	SigHashFlag *sighash.Flag
+}
+
+// Sign produces <sig> for outputs locked to a public key, or <sig> <pubkey>
+// for outputs locked to a public key hash.
+func (p *PushDrop) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	if tx.Inputs[inputIndex].SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+
+	d, err := Decode(tx.Inputs[inputIndex].SourceTxScript())
+	if err != nil {
+		return nil, err
+	}
+	pubKey := p.PrivateKey.PubKey()
+	if d.PublicKey != nil && !d.PublicKey.IsEqual(pubKey) {
+		return nil, ErrKeyMismatch
+	} else if d.PublicKeyHash != nil && !bytes.Equal(d.PublicKeyHash, crypto.Hash160(pubKey.Compressed())) {
+		return nil, ErrKeyMismatch
+	}
+
+	sh, err := tx.CalcInputSignatureHash(inputIndex, *p.SigHashFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := p.PrivateKey.Sign(sh)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBuf := make([]byte, 0)
+	sigBuf = append(sigBuf, sig.Serialize()...)
+	sigBuf = append(sigBuf, uint8(*p.SigHashFlag))
+
+	s := &script.Script{}
+	if err = s.AppendPushData(sigBuf); err != nil {
+		return nil, err
+	}
+	if d.PublicKeyHash != nil {
+		if err = s.AppendPushData(pubKey.Compressed()); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// EstimateLength returns the worst case length of the unlocking script: a
+// signature push, plus the push of a compressed public key when the output
+// is locked to a public key hash.
+func (p *PushDrop) EstimateLength(tx *transaction.Transaction, inputIndex uint32) uint32 {
+	if tx != nil && int(inputIndex) < len(tx.Inputs) {
+		if d, err := Decode(tx.Inputs[inputIndex].SourceTxScript()); err == nil && d.PublicKeyHash == nil {
+			return transaction.MaxSignaturePushLength
+		}
+	}
+	return transaction.P2PKHUnlockingScriptLength
+}
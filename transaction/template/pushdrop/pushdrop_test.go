@@ -0,0 +1,111 @@
+package pushdrop_test
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/pushdrop"
+	"github.com/stretchr/testify/require"
+)
+
+var fields = [][]byte{
+	[]byte("token"),
+	{},
+	{5},
+	{0x81},
+	make([]byte, 300),
+}
+
+func TestLockDecode(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+
+	for _, position := range []pushdrop.LockPosition{pushdrop.LockAfter, pushdrop.LockBefore} {
+		s, err := pushdrop.Lock(priv.PubKey(), fields, position)
+		require.NoError(t, err)
+
+		d, err := pushdrop.Decode(s)
+		require.NoError(t, err)
+		require.Equal(t, position, d.LockPosition)
+		require.True(t, priv.PubKey().IsEqual(d.PublicKey))
+		require.Nil(t, d.PublicKeyHash)
+		require.Equal(t, fields, d.Fields)
+
+		s, err = pushdrop.LockPKH(address, fields[:2], position)
+		require.NoError(t, err)
+
+		d, err = pushdrop.Decode(s)
+		require.NoError(t, err)
+		require.Equal(t, position, d.LockPosition)
+		require.Nil(t, d.PublicKey)
+		require.Equal(t, []byte(address.PublicKeyHash), d.PublicKeyHash)
+		require.Equal(t, fields[:2], d.Fields)
+	}
+
+	p2pkh, err := script.NewFromHex("76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac")
+	require.NoError(t, err)
+	_, err = pushdrop.Decode(p2pkh)
+	require.ErrorIs(t, err, pushdrop.ErrNotPushDrop)
+
+	_, err = pushdrop.Lock(priv.PubKey(), nil, pushdrop.LockAfter)
+	require.ErrorIs(t, err, pushdrop.ErrNoFields)
+}
+
+func TestSign(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+
+	lockP2PK, err := pushdrop.Lock(priv.PubKey(), fields, pushdrop.LockBefore)
+	require.NoError(t, err)
+	lockP2PKH, err := pushdrop.LockPKH(address, fields, pushdrop.LockAfter)
+	require.NoError(t, err)
+
+	for name, lockingScript := range map[string]*script.Script{"p2pk": lockP2PK, "p2pkh": lockP2PKH} {
+		t.Run(name, func(t *testing.T) {
+			unlocker, err := pushdrop.Unlock(priv, nil)
+			require.NoError(t, err)
+
+			tx := transaction.NewTransaction()
+			require.NoError(t, tx.AddInputFrom(
+				"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+				0,
+				lockingScript.String(),
+				1,
+				unlocker,
+			))
+			out, err := pushdrop.Lock(priv.PubKey(), [][]byte{[]byte("next")}, pushdrop.LockBefore)
+			require.NoError(t, err)
+			tx.AddOutput(&transaction.TransactionOutput{Satoshis: 1, LockingScript: out})
+			require.NoError(t, tx.Sign())
+			require.LessOrEqual(t, uint32(len(*tx.Inputs[0].UnlockingScript)), unlocker.EstimateLength(tx, 0))
+
+			require.NoError(t, interpreter.NewEngine().Execute(
+				interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+				interpreter.WithForkID(),
+				interpreter.WithAfterGenesis(),
+			))
+		})
+	}
+
+	other, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	unlocker, err := pushdrop.Unlock(other, nil)
+	require.NoError(t, err)
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+		0,
+		lockP2PK.String(),
+		1,
+		unlocker,
+	))
+	_, err = unlocker.Sign(tx, 0)
+	require.ErrorIs(t, err, pushdrop.ErrKeyMismatch)
+}
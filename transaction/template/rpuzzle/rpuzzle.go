@@ -0,0 +1,229 @@
+package rpuzzle
+
+import (
+	e "crypto/ecdsa"
+	"crypto/sha1" //nolint:gosec // OP_SHA1 support requires this
+	"errors"
+	"math/big"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	ecdsa "github.com/bsv-blockchain/go-sdk/primitives/ecdsa"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+var (
+	ErrNoValue         = errors.New("puzzle value not supplied")
+	ErrNoK             = errors.New("k value not supplied")
+	ErrUnknownType     = errors.New("unknown r-puzzle type")
+	ErrKOutOfRange     = errors.New("k value is out of range")
+	ErrNotRPuzzle      = errors.New("script is not an r-puzzle")
+	ErrBadPuzzleLength = errors.New("puzzle value has the wrong length for its type")
+)
+
+// PuzzleType determines how the R value of the signature is
+// committed to in the locking script.
+type PuzzleType string
+
+const (
+	Raw       PuzzleType = "raw"
+	SHA1      PuzzleType = "SHA1"
+	SHA256    PuzzleType = "SHA256"
+	HASH256   PuzzleType = "HASH256"
+	RIPEMD160 PuzzleType = "RIPEMD160"
+	HASH160   PuzzleType = "HASH160"
+)
+
+var hashOps = map[PuzzleType]byte{
+	SHA1:      script.OpSHA1,
+	SHA256:    script.OpSHA256,
+	HASH256:   script.OpHASH256,
+	RIPEMD160: script.OpRIPEMD160,
+	HASH160:   script.OpHASH160,
+}
+
+var hashLengths = map[PuzzleType]int{
+	SHA1:      20,
+	SHA256:    32,
+	HASH256:   32,
+	RIPEMD160: 20,
+	HASH160:   20,
+}
+
+// extractR is the script prefix which copies the signature and
+// extracts its DER encoded R value:
+// OP_OVER OP_3 OP_SPLIT OP_NIP OP_1 OP_SPLIT OP_SWAP OP_SPLIT OP_DROP
+var extractR = []byte{
+	script.OpOVER, script.Op3, script.OpSPLIT, script.OpNIP,
+	script.Op1, script.OpSPLIT, script.OpSWAP, script.OpSPLIT, script.OpDROP,
+}
+
+// Lock creates an R-puzzle locking script which can be unlocked by any
+// signature whose R value, hashed according to puzzleType, matches value.
+func Lock(value []byte, puzzleType PuzzleType) (*script.Script, error) {
+	if len(value) == 0 {
+		return nil, ErrNoValue
+	}
+	if puzzleType != Raw {
+		l, ok := hashLengths[puzzleType]
+		if !ok {
+			return nil, ErrUnknownType
+		}
+		if len(value) != l {
+			return nil, ErrBadPuzzleLength
+		}
+	}
+
+	s := script.NewFromBytes(append([]byte{}, extractR...))
+	if puzzleType != Raw {
+		_ = s.AppendOpcodes(hashOps[puzzleType])
+	}
+	if err := s.AppendPushData(value); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(script.OpEQUALVERIFY, script.OpCHECKSIG)
+
+	return s, nil
+}
+
+// Decode returns the puzzle type and value committed to by an R-puzzle
+// locking script.
+func Decode(s *script.Script) (PuzzleType, []byte, error) {
+	if s == nil || len(*s) <= len(extractR) || !s.Slice(0, uint64(len(extractR))).EqualsBytes(extractR) {
+		return "", nil, ErrNotRPuzzle
+	}
+	parts, err := script.DecodeScript((*s)[len(extractR):])
+	if err != nil {
+		return "", nil, ErrNotRPuzzle
+	}
+
+	puzzleType := Raw
+	if len(parts) == 4 {
+		puzzleType = ""
+		for t, op := range hashOps {
+			if parts[0].Op == op {
+				puzzleType = t
+			}
+		}
+		if puzzleType == "" {
+			return "", nil, ErrNotRPuzzle
+		}
+		parts = parts[1:]
+	}
+	if len(parts) != 3 || len(parts[0].Data) == 0 ||
+		parts[1].Op != script.OpEQUALVERIFY || parts[2].Op != script.OpCHECKSIG {
+		return "", nil, ErrNotRPuzzle
+	}
+
+	return puzzleType, parts[0].Data, nil
+}
+
+// RValue returns the DER encoded R value of signatures produced with k,
+// which is the raw value an R-puzzle locks to.
+func RValue(k *big.Int) ([]byte, error) {
+	if k == nil {
+		return nil, ErrNoK
+	}
+	curve := ec.S256()
+	if k.Sign() <= 0 || k.Cmp(curve.N) >= 0 {
+		return nil, ErrKOutOfRange
+	}
+	x, _ := curve.ScalarBaseMult(k.Bytes())
+	r := new(big.Int).Mod(x, curve.N).Bytes()
+	// DER integers are signed, so pad when the high bit is set.
+	if len(r) > 0 && r[0]&0x80 != 0 {
+		r = append([]byte{0x00}, r...)
+	}
+	return r, nil
+}
+
+// Value returns the value to lock to for the given k and puzzle type.
+func Value(k *big.Int, puzzleType PuzzleType) ([]byte, error) {
+	r, err := RValue(k)
+	if err != nil {
+		return nil, err
+	}
+	switch puzzleType {
+	case Raw:
+		return r, nil
+	case SHA1:
+		h := sha1.Sum(r) //nolint:gosec // OP_SHA1 support requires this
+		return h[:], nil
+	case SHA256:
+		return crypto.Sha256(r), nil
+	case HASH256:
+		return crypto.Sha256d(r), nil
+	case RIPEMD160:
+		return crypto.Ripemd160(r), nil
+	case HASH160:
+		return crypto.Hash160(r), nil
+	}
+	return nil, ErrUnknownType
+}
+
+// Unlock creates an unlocking template which signs with the supplied k
+// value. Any private key can be used, as the puzzle only checks the R value
+// of the signature; if key is nil a random key is generated.
+func Unlock(key *ec.PrivateKey, k *big.Int, sigHashFlag *sighash.Flag) (*RPuzzle, error) {
+	if k == nil {
+		return nil, ErrNoK
+	}
+	if key == nil {
+		var err error
+		if key, err = ec.NewPrivateKey(); err != nil {
+			return nil, err
+		}
+	}
+	if sigHashFlag == nil {
+		shf := sighash.AllForkID
+		sigHashFlag = &shf
+	}
+	return &RPuzzle{
+		PrivateKey:  key,
+		K:           k,
+		SigHashFlag: sigHashFlag,
+	}, nil
+}
+
+type RPuzzle struct {
+	PrivateKey  *ec.PrivateKey
+	K           *big.Int
+	SigHashFlag *sighash.Flag
+}
+
+func (p *RPuzzle) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	if tx.Inputs[inputIndex].SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+
+	sh, err := tx.CalcInputSignatureHash(inputIndex, *p.SigHashFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := ecdsa.SignWithCustomK(sh, (*e.PrivateKey)(p.PrivateKey), true, p.K)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBuf := make([]byte, 0)
+	sigBuf = append(sigBuf, sig.Serialize()...)
+	sigBuf = append(sigBuf, uint8(*p.SigHashFlag))
+
+	s := &script.Script{}
+	if err = s.AppendPushData(sigBuf); err != nil {
+		return nil, err
+	} else if err = s.AppendPushData(p.PrivateKey.PubKey().Compressed()); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// EstimateLength returns the worst case length of the unlocking script. The
+// signature is low-S, so it is the same as for P2PKH.
+func (p *RPuzzle) EstimateLength(_ *transaction.Transaction, inputIndex uint32) uint32 {
+	return transaction.P2PKHUnlockingScriptLength
+}
@@ -0,0 +1,99 @@
+package rpuzzle_test
+
+import (
+	"math/big"
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/rpuzzle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPuzzle(t *testing.T) {
+	kPriv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	k := kPriv.D
+
+	for _, puzzleType := range []rpuzzle.PuzzleType{
+		rpuzzle.Raw,
+		rpuzzle.SHA1,
+		rpuzzle.SHA256,
+		rpuzzle.HASH256,
+		rpuzzle.RIPEMD160,
+		rpuzzle.HASH160,
+	} {
+		t.Run(string(puzzleType), func(t *testing.T) {
+			value, err := rpuzzle.Value(k, puzzleType)
+			require.NoError(t, err)
+
+			lockingScript, err := rpuzzle.Lock(value, puzzleType)
+			require.NoError(t, err)
+
+			decodedType, decodedValue, err := rpuzzle.Decode(lockingScript)
+			require.NoError(t, err)
+			require.Equal(t, puzzleType, decodedType)
+			require.Equal(t, value, decodedValue)
+
+			unlocker, err := rpuzzle.Unlock(nil, k, nil)
+			require.NoError(t, err)
+
+			tx := transaction.NewTransaction()
+			require.NoError(t, tx.AddInputFrom(
+				"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+				0,
+				lockingScript.String(),
+				1000,
+				unlocker,
+			))
+			require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 900))
+			require.NoError(t, tx.Sign())
+			require.LessOrEqual(t, uint32(len(*tx.Inputs[0].UnlockingScript)), unlocker.EstimateLength(tx, 0))
+
+			require.NoError(t, interpreter.NewEngine().Execute(
+				interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+				interpreter.WithForkID(),
+				interpreter.WithAfterGenesis(),
+			))
+		})
+	}
+}
+
+func TestRPuzzleWrongK(t *testing.T) {
+	value, err := rpuzzle.Value(big.NewInt(12345), rpuzzle.HASH160)
+	require.NoError(t, err)
+	lockingScript, err := rpuzzle.Lock(value, rpuzzle.HASH160)
+	require.NoError(t, err)
+
+	unlocker, err := rpuzzle.Unlock(nil, big.NewInt(54321), nil)
+	require.NoError(t, err)
+
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+		0,
+		lockingScript.String(),
+		1000,
+		unlocker,
+	))
+	require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 900))
+	require.NoError(t, tx.Sign())
+
+	require.Error(t, interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	))
+}
+
+func TestLockErrors(t *testing.T) {
+	_, err := rpuzzle.Lock(nil, rpuzzle.Raw)
+	require.ErrorIs(t, err, rpuzzle.ErrNoValue)
+	_, err = rpuzzle.Lock(make([]byte, 32), rpuzzle.HASH160)
+	require.ErrorIs(t, err, rpuzzle.ErrBadPuzzleLength)
+	_, err = rpuzzle.Lock(make([]byte, 20), "MD5")
+	require.ErrorIs(t, err, rpuzzle.ErrUnknownType)
+	_, err = rpuzzle.RValue(big.NewInt(0))
+	require.ErrorIs(t, err, rpuzzle.ErrKOutOfRange)
+}
@@ -0,0 +1,324 @@
+// Package template classifies locking scripts against a registry of known
+// script templates, so that wallets can recognise outputs and work out which
+// of them their keys can spend.
+package template
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/hashpuzzle"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/multisig"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/ordlock"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/pushdrop"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/rpuzzle"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/timelock"
+)
+
+// Names of the built-in templates.
+const (
+	NamePubKey     = script.ScriptTypePubKey
+	NamePubKeyHash = script.ScriptTypePubKeyHash
+	NameMultiSig   = script.ScriptTypeMultiSig
+	NameNullData   = script.ScriptTypeNullData
+	NameScriptHash = "scripthash"
+	NamePushDrop   = "pushdrop"
+	NameTimeLock   = "timelock"
+	NameHashPuzzle = "hashpuzzle"
+	NameRPuzzle    = "rpuzzle"
+	NameOrdLock    = "ordlock"
+)
+
+var (
+	ErrUnknownScript     = errors.New("script does not match any registered template")
+	ErrDuplicateTemplate = errors.New("template already registered")
+	ErrInvalidTemplate   = errors.New("template must have a name, matcher and decoder")
+)
+
+// Classification is the result of matching a locking script to a template.
+type Classification struct {
+	// Template is the name of the matched template.
+	Template string
+	// PublicKeys are the public keys the script is locked to, if any.
+	PublicKeys []*ec.PublicKey
+	// PublicKeyHashes are the public key hashes the script is locked to, if any.
+	PublicKeyHashes [][]byte
+	// Threshold is the number of keys which must sign to spend the output,
+	// or zero when it is not locked to keys.
+	Threshold int
+	// Fields are the data fields carried by the script, if any.
+	Fields [][]byte
+	// Params holds any template specific parameters, such as
+	// *pushdrop.Decoded or TimeLockParams.
+	Params any
+	// Spendable reports whether the keys passed to Classify satisfy the
+	// key requirements of the script. Other conditions, such as hash
+	// preimages or timelocks, are described by Params and are not checked.
+	Spendable bool
+}
+
+// CanSpend reports whether the keys satisfy the key requirements of the
+// classified script.
+func (c *Classification) CanSpend(keys ...*ec.PrivateKey) bool {
+	if c.Threshold == 0 {
+		return false
+	}
+	matched := 0
+	for _, pk := range c.PublicKeys {
+		for _, key := range keys {
+			if key != nil && key.PubKey().IsEqual(pk) {
+				matched++
+				break
+			}
+		}
+	}
+	for _, pkh := range c.PublicKeyHashes {
+		for _, key := range keys {
+			if key == nil {
+				continue
+			}
+			pub := key.PubKey()
+			if bytes.Equal(pkh, crypto.Hash160(pub.Compressed())) ||
+				bytes.Equal(pkh, crypto.Hash160(pub.Uncompressed())) {
+				matched++
+				break
+			}
+		}
+	}
+	return matched >= c.Threshold
+}
+
+// TimeLockParams are the parameters of a timelock output.
+type TimeLockParams struct {
+	Kind  timelock.Kind
+	Value uint32
+}
+
+// HashPuzzleParams are the parameters of a hash puzzle output.
+type HashPuzzleParams struct {
+	SecretHash []byte
+}
+
+// RPuzzleParams are the parameters of an R-puzzle output.
+type RPuzzleParams struct {
+	Type  rpuzzle.PuzzleType
+	Value []byte
+}
+
+// OrdLockParams are the parameters of an ordlock listing. The listed
+// public key hash is the seller's, who can cancel the listing.
+type OrdLockParams struct {
+	Payment *transaction.TransactionOutput
+}
+
+// Template describes how to recognise and decode a locking script.
+// Match should be cheap, Decode is only called on scripts which match. A
+// script which matches but fails to decode is not of the template.
+type Template struct {
+	Name   string
+	Match  func(s *script.Script) bool
+	Decode func(s *script.Script) (*Classification, error)
+}
+
+// Registry holds a set of templates which are tried in registration order.
+type Registry struct {
+	mu        sync.RWMutex
+	templates []*Template
+}
+
+// NewRegistry creates a registry with the given templates.
+func NewRegistry(templates ...*Template) (*Registry, error) {
+	r := &Registry{}
+	for _, t := range templates {
+		if err := r.Register(t); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds a template to the registry. Templates are tried in the order
+// they were registered, so more specific templates should be added first.
+func (r *Registry) Register(t *Template) error {
+	if t == nil || t.Name == "" || t.Match == nil || t.Decode == nil {
+		return ErrInvalidTemplate
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.templates {
+		if existing.Name == t.Name {
+			return ErrDuplicateTemplate
+		}
+	}
+	r.templates = append(r.templates, t)
+	return nil
+}
+
+// Classify matches the script against the registered templates and decodes
+// it with the first that matches and decodes successfully. Spendable is set
+// according to keys.
+func (r *Registry) Classify(s *script.Script, keys ...*ec.PrivateKey) (*Classification, error) {
+	if s == nil || len(*s) == 0 {
+		return nil, ErrUnknownScript
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, t := range r.templates {
+		if !t.Match(s) {
+			continue
+		}
+		c, err := t.Decode(s)
+		if err != nil {
+			continue
+		}
+		c.Template = t.Name
+		c.Spendable = c.CanSpend(keys...)
+		return c, nil
+	}
+	return nil, ErrUnknownScript
+}
+
+// DefaultTemplates returns the built-in templates in the order they are tried.
+func DefaultTemplates() []*Template {
+	return []*Template{
+		{Name: NamePubKeyHash, Match: (*script.Script).IsP2PKH, Decode: decodePubKeyHash},
+		{Name: NamePubKey, Match: (*script.Script).IsP2PK, Decode: decodePubKey},
+		{Name: NameMultiSig, Match: (*script.Script).IsMultiSigOut, Decode: decodeMultiSig},
+		{Name: NameScriptHash, Match: (*script.Script).IsP2SH, Decode: decodeScriptHash},
+		{Name: NameNullData, Match: (*script.Script).IsData, Decode: decodeNullData},
+		{Name: NameHashPuzzle, Match: decodes(decodeHashPuzzle), Decode: decodeHashPuzzle},
+		{Name: NameTimeLock, Match: decodes(decodeTimeLock), Decode: decodeTimeLock},
+		{Name: NameRPuzzle, Match: decodes(decodeRPuzzle), Decode: decodeRPuzzle},
+		{Name: NamePushDrop, Match: decodes(decodePushDrop), Decode: decodePushDrop},
+		{Name: NameOrdLock, Match: ordlock.IsOrdLock, Decode: decodeOrdLock},
+	}
+}
+
+var defaultRegistry = &Registry{templates: DefaultTemplates()}
+
+// Register adds a template to the default registry, after the built-in templates.
+func Register(t *Template) error {
+	return defaultRegistry.Register(t)
+}
+
+// Classify classifies the script using the default registry.
+func Classify(s *script.Script, keys ...*ec.PrivateKey) (*Classification, error) {
+	return defaultRegistry.Classify(s, keys...)
+}
+
+// decodes adapts a decoder into a matcher, for templates which
+// have no cheaper way to recognise their scripts.
+func decodes(decode func(*script.Script) (*Classification, error)) func(*script.Script) bool {
+	return func(s *script.Script) bool {
+		_, err := decode(s)
+		return err == nil
+	}
+}
+
+func decodePubKeyHash(s *script.Script) (*Classification, error) {
+	pkh, err := s.PublicKeyHash()
+	if err != nil {
+		return nil, err
+	}
+	return &Classification{PublicKeyHashes: [][]byte{pkh}, Threshold: 1}, nil
+}
+
+func decodePubKey(s *script.Script) (*Classification, error) {
+	pubKey, err := s.PubKey()
+	if err != nil {
+		return nil, err
+	}
+	return &Classification{PublicKeys: []*ec.PublicKey{pubKey}, Threshold: 1}, nil
+}
+
+func decodeMultiSig(s *script.Script) (*Classification, error) {
+	pubKeys, m, err := multisig.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Classification{PublicKeys: pubKeys, Threshold: m}, nil
+}
+
+func decodeScriptHash(s *script.Script) (*Classification, error) {
+	return &Classification{Params: []byte(*s)[2:22]}, nil
+}
+
+func decodeNullData(s *script.Script) (*Classification, error) {
+	b := []byte(*s)
+	if b[0] == script.OpFALSE {
+		b = b[1:]
+	}
+	// Anything after OP_RETURN is never executed, so tolerate trailing garbage.
+	chunks, _ := script.DecodeScript(b[1:])
+	fields := make([][]byte, 0, len(chunks))
+	for _, chunk := range chunks {
+		if chunk.Op > script.OpPUSHDATA4 {
+			break
+		}
+		fields = append(fields, chunk.Data)
+	}
+	return &Classification{Fields: fields}, nil
+}
+
+func decodeHashPuzzle(s *script.Script) (*Classification, error) {
+	secretHash, pkh, err := hashpuzzle.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Classification{
+		PublicKeyHashes: [][]byte{pkh},
+		Threshold:       1,
+		Params:          HashPuzzleParams{SecretHash: secretHash},
+	}, nil
+}
+
+func decodeTimeLock(s *script.Script) (*Classification, error) {
+	kind, value, pkh, err := timelock.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Classification{
+		PublicKeyHashes: [][]byte{pkh},
+		Threshold:       1,
+		Params:          TimeLockParams{Kind: kind, Value: value},
+	}, nil
+}
+
+func decodeRPuzzle(s *script.Script) (*Classification, error) {
+	puzzleType, value, err := rpuzzle.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Classification{Params: RPuzzleParams{Type: puzzleType, Value: value}}, nil
+}
+
+func decodePushDrop(s *script.Script) (*Classification, error) {
+	d, err := pushdrop.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	c := &Classification{Fields: d.Fields, Threshold: 1, Params: d}
+	if d.PublicKey != nil {
+		c.PublicKeys = []*ec.PublicKey{d.PublicKey}
+	} else {
+		c.PublicKeyHashes = [][]byte{d.PublicKeyHash}
+	}
+	return c, nil
+}
+
+func decodeOrdLock(s *script.Script) (*Classification, error) {
+	pkh, payment, err := ordlock.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Classification{
+		PublicKeyHashes: [][]byte{pkh},
+		Threshold:       1,
+		Params:          OrdLockParams{Payment: payment},
+	}, nil
+}
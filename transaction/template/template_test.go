@@ -0,0 +1,143 @@
+package template_test
+
+import (
+	"errors"
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/hashpuzzle"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/multisig"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/ordlock"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pk"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/pushdrop"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/rpuzzle"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/timelock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	other, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+	pkh := []byte(address.PublicKeyHash)
+
+	lockP2PKH, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	lockP2PK, err := p2pk.Lock(priv.PubKey())
+	require.NoError(t, err)
+	lockMultiSig, err := multisig.Lock([]*ec.PublicKey{priv.PubKey(), other.PubKey()}, 2)
+	require.NoError(t, err)
+	lockHashPuzzle, err := hashpuzzle.LockSecret([]byte("secret"), address)
+	require.NoError(t, err)
+	lockTimeLock, err := timelock.LockAbsolute(800000, address)
+	require.NoError(t, err)
+	lockRPuzzle, err := rpuzzle.Lock(make([]byte, 20), rpuzzle.HASH160)
+	require.NoError(t, err)
+	lockPushDrop, err := pushdrop.Lock(priv.PubKey(), [][]byte{[]byte("token")}, pushdrop.LockBefore)
+	require.NoError(t, err)
+	lockOrdLock, err := ordlock.Lock(address, &transaction.TransactionOutput{Satoshis: 1000, LockingScript: lockP2PKH})
+	require.NoError(t, err)
+	lockData, err := script.NewFromASM("OP_FALSE OP_RETURN 68656c6c6f 776f726c64")
+	require.NoError(t, err)
+	lockScriptHash, err := script.NewFromHex("a914" + "0000000000000000000000000000000000000000" + "87")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		script    *script.Script
+		template  string
+		spendable bool
+		check     func(t *testing.T, c *template.Classification)
+	}{
+		{"p2pkh", lockP2PKH, template.NamePubKeyHash, true, func(t *testing.T, c *template.Classification) {
+			require.Equal(t, [][]byte{pkh}, c.PublicKeyHashes)
+		}},
+		{"p2pk", lockP2PK, template.NamePubKey, true, func(t *testing.T, c *template.Classification) {
+			require.True(t, priv.PubKey().IsEqual(c.PublicKeys[0]))
+		}},
+		{"multisig", lockMultiSig, template.NameMultiSig, false, func(t *testing.T, c *template.Classification) {
+			require.Len(t, c.PublicKeys, 2)
+			require.Equal(t, 2, c.Threshold)
+			require.True(t, c.CanSpend(priv, other))
+		}},
+		{"hash puzzle", lockHashPuzzle, template.NameHashPuzzle, true, func(t *testing.T, c *template.Classification) {
+			require.IsType(t, template.HashPuzzleParams{}, c.Params)
+		}},
+		{"timelock", lockTimeLock, template.NameTimeLock, true, func(t *testing.T, c *template.Classification) {
+			require.Equal(t, template.TimeLockParams{Kind: timelock.Absolute, Value: 800000}, c.Params)
+		}},
+		{"rpuzzle", lockRPuzzle, template.NameRPuzzle, false, func(t *testing.T, c *template.Classification) {
+			require.Equal(t, rpuzzle.HASH160, c.Params.(template.RPuzzleParams).Type)
+		}},
+		{"pushdrop", lockPushDrop, template.NamePushDrop, true, func(t *testing.T, c *template.Classification) {
+			require.Equal(t, [][]byte{[]byte("token")}, c.Fields)
+		}},
+		{"ordlock", lockOrdLock, template.NameOrdLock, true, func(t *testing.T, c *template.Classification) {
+			require.Equal(t, uint64(1000), c.Params.(template.OrdLockParams).Payment.Satoshis)
+		}},
+		{"data", lockData, template.NameNullData, false, func(t *testing.T, c *template.Classification) {
+			require.Equal(t, [][]byte{[]byte("hello"), []byte("world")}, c.Fields)
+		}},
+		{"script hash", lockScriptHash, template.NameScriptHash, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := template.Classify(tt.script, priv)
+			require.NoError(t, err)
+			require.Equal(t, tt.template, c.Template)
+			require.Equal(t, tt.spendable, c.Spendable)
+			if tt.check != nil {
+				tt.check(t, c)
+			}
+
+			c, err = template.Classify(tt.script, other)
+			require.NoError(t, err)
+			require.False(t, c.Spendable)
+		})
+	}
+
+	_, err = template.Classify(script.NewFromBytes([]byte{script.OpTRUE}))
+	require.ErrorIs(t, err, template.ErrUnknownScript)
+}
+
+func TestRegistry(t *testing.T) {
+	custom := &template.Template{
+		Name:  "true",
+		Match: func(s *script.Script) bool { return len(*s) == 1 && (*s)[0] == script.OpTRUE },
+		Decode: func(s *script.Script) (*template.Classification, error) {
+			return &template.Classification{}, nil
+		},
+	}
+
+	// A template which matches but fails to decode is passed over.
+	broken := &template.Template{
+		Name:  "broken",
+		Match: func(s *script.Script) bool { return true },
+		Decode: func(s *script.Script) (*template.Classification, error) {
+			return nil, errors.New("broken")
+		},
+	}
+
+	r, err := template.NewRegistry(broken, custom)
+	require.NoError(t, err)
+	require.ErrorIs(t, r.Register(custom), template.ErrDuplicateTemplate)
+	require.ErrorIs(t, r.Register(&template.Template{Name: "empty"}), template.ErrInvalidTemplate)
+
+	c, err := r.Classify(script.NewFromBytes([]byte{script.OpTRUE}))
+	require.NoError(t, err)
+	require.Equal(t, "true", c.Template)
+	require.False(t, c.Spendable)
+
+	p2pkhScript, err := script.NewFromHex("76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac")
+	require.NoError(t, err)
+	_, err = r.Classify(p2pkhScript)
+	require.ErrorIs(t, err, template.ErrUnknownScript)
+}
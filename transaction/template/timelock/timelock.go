@@ -0,0 +1,356 @@
+// Package timelock provides templates for outputs which cannot be spent
+// before an absolute (OP_CHECKLOCKTIMEVERIFY) or relative
+// (OP_CHECKSEQUENCEVERIFY) time, with a P2PKH condition inside.
+//
+// Note that after the Genesis upgrade both opcodes are treated as NOPs for
+// outputs created after Genesis activation, so the lock is only enforced by
+// the interpreter for UTXOs that predate it.
+package timelock
+
+import (
+	"bytes"
+	"errors"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	crypto "github.com/bsv-blockchain/go-sdk/primitives/hash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+// LockTimeThreshold is the value below which a lock time is
+// interpreted as a block height rather than a unix timestamp.
+const LockTimeThreshold = 500000000
+
+var (
+	ErrBadPublicKeyHash = errors.New("invalid public key hash")
+	ErrBadSequence      = errors.New("relative lock sequence must only use the lock time bits")
+	ErrNoPrivateKey     = errors.New("private key not supplied")
+	ErrNotTimeLock      = errors.New("script is not a timelock output")
+	ErrKeyMismatch      = errors.New("private key does not match the locking key")
+	ErrImmature         = errors.New("timelocked output is not yet spendable")
+	ErrLockTimeConflict = errors.New("transaction lock time conflicts with the output lock time")
+	ErrNotPrepared      = errors.New("transaction lock time, version or sequence number does not satisfy the lock")
+)
+
+// Kind identifies the type of timelock.
+type Kind int
+
+const (
+	// Absolute locks until a block height or time using OP_CHECKLOCKTIMEVERIFY.
+	Absolute Kind = iota
+	// Relative locks for a number of blocks or 512 second intervals after
+	// the output is mined using OP_CHECKSEQUENCEVERIFY.
+	Relative
+)
+
+// ChainState describes the chain when spending a timelocked output. Height
+// and MedianTime refer to the current chain tip. SourceHeight and
+// SourceMedianTime refer to the block that mined the output being spent and
+// are only needed for relative locks.
+type ChainState struct {
+	Height           uint32
+	MedianTime       uint32
+	SourceHeight     uint32
+	SourceMedianTime uint32
+}
+
+// RelativeBlocks returns the sequence for a relative lock of n blocks.
+func RelativeBlocks(n uint16) uint32 {
+	return uint32(n)
+}
+
+// RelativeSeconds returns the sequence for a relative lock of at least the
+// given number of seconds, rounded up to the 512 second granularity.
+func RelativeSeconds(seconds uint32) uint32 {
+	units := (seconds + 511) >> 9
+	if units > transaction.SequenceLockTimeMask {
+		units = transaction.SequenceLockTimeMask
+	}
+	return transaction.SequenceLockTimeIsSeconds | units
+}
+
+// LockAbsolute creates a locking script which can be spent by the owner of
+// the address once the block height or unix time lockTime has passed:
+//
+// <lockTime> OP_CHECKLOCKTIMEVERIFY OP_DROP OP_DUP OP_HASH160 <pkh> OP_EQUALVERIFY OP_CHECKSIG
+func LockAbsolute(lockTime uint32, a *script.Address) (*script.Script, error) {
+	return lock(lockTime, script.OpCHECKLOCKTIMEVERIFY, a)
+}
+
+// LockRelative creates a locking script which can be spent by the owner of
+// the address once the relative lock encoded in sequence has passed since the
+// output was mined. Use RelativeBlocks or RelativeSeconds to build sequence.
+//
+// <sequence> OP_CHECKSEQUENCEVERIFY OP_DROP OP_DUP OP_HASH160 <pkh> OP_EQUALVERIFY OP_CHECKSIG
+func LockRelative(sequence uint32, a *script.Address) (*script.Script, error) {
+	if sequence&^uint32(transaction.SequenceLockTimeIsSeconds|transaction.SequenceLockTimeMask) != 0 {
+		return nil, ErrBadSequence
+	}
+	return lock(sequence, script.OpCHECKSEQUENCEVERIFY, a)
+}
+
+func lock(value uint32, op byte, a *script.Address) (*script.Script, error) {
+	if len(a.PublicKeyHash) != 20 {
+		return nil, ErrBadPublicKeyHash
+	}
+	s := &script.Script{}
+	if err := appendNumber(s, value); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(op, script.OpDROP, script.OpDUP, script.OpHASH160)
+	if err := s.AppendPushData(a.PublicKeyHash); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(script.OpEQUALVERIFY, script.OpCHECKSIG)
+	return s, nil
+}
+
+// Decode returns the kind of timelock, its lock time or sequence value and
+// the public key hash of a timelock locking script.
+func Decode(s *script.Script) (Kind, uint32, []byte, error) {
+	if s == nil {
+		return 0, 0, nil, ErrNotTimeLock
+	}
+	parts, err := s.Chunks()
+	if err != nil || len(parts) != 8 ||
+		parts[2].Op != script.OpDROP ||
+		parts[3].Op != script.OpDUP ||
+		parts[4].Op != script.OpHASH160 ||
+		len(parts[5].Data) != 20 ||
+		parts[6].Op != script.OpEQUALVERIFY ||
+		parts[7].Op != script.OpCHECKSIG {
+		return 0, 0, nil, ErrNotTimeLock
+	}
+
+	var kind Kind
+	switch parts[1].Op {
+	case script.OpCHECKLOCKTIMEVERIFY:
+		kind = Absolute
+	case script.OpCHECKSEQUENCEVERIFY:
+		kind = Relative
+	default:
+		return 0, 0, nil, ErrNotTimeLock
+	}
+
+	value, ok := decodeNumber(parts[0])
+	if !ok {
+		return 0, 0, nil, ErrNotTimeLock
+	}
+
+	return kind, value, parts[5].Data, nil
+}
+
+// appendNumber pushes n as a minimally encoded script number.
+func appendNumber(s *script.Script, n uint32) error {
+	if n == 0 {
+		return s.AppendOpcodes(script.Op0)
+	}
+	if n <= 16 {
+		return s.AppendOpcodes(script.Op1 + byte(n-1))
+	}
+	b := make([]byte, 0, 5)
+	for v := n; v > 0; v >>= 8 {
+		b = append(b, byte(v))
+	}
+	// The most significant bit is the sign, so pad positive numbers which use it.
+	if b[len(b)-1]&0x80 != 0 {
+		b = append(b, 0x00)
+	}
+	return s.AppendPushData(b)
+}
+
+func decodeNumber(chunk *script.ScriptChunk) (uint32, bool) {
+	switch {
+	case chunk.Op == script.Op0:
+		return 0, true
+	case chunk.Op >= script.Op1 && chunk.Op <= script.Op16:
+		return uint32(chunk.Op-script.Op1) + 1, true
+	case len(chunk.Data) == 0 || len(chunk.Data) > 5 || chunk.Data[len(chunk.Data)-1]&0x80 != 0:
+		return 0, false
+	}
+	var v uint64
+	for i := len(chunk.Data) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(chunk.Data[i])
+	}
+	if v > 0xffffffff {
+		return 0, false
+	}
+	return uint32(v), true
+}
+
+func Unlock(key *ec.PrivateKey, chain ChainState, sigHashFlag *sighash.Flag) (*TimeLock, error) {
+	if key == nil {
+		return nil, ErrNoPrivateKey
+	}
+	if sigHashFlag == nil {
+		shf := sighash.AllForkID
+		sigHashFlag = &shf
+	}
+	return &TimeLock{
+		PrivateKey:  key,
+		Chain:       chain,
+		SigHashFlag: sigHashFlag,
+	}, nil
+}
+
+type TimeLock struct {
+	PrivateKey  *ec.PrivateKey
+	Chain       ChainState
+	SigHashFlag *sighash.Flag
+}
+
+// Apply checks that the output spent by the input is mature and sets the
+// transaction lock time, version and input sequence number needed to
+// satisfy the lock. Since the fields it changes are covered by every
+// input's signature, Apply must be called before any input of the
+// transaction is signed.
+func (p *TimeLock) Apply(tx *transaction.Transaction, inputIndex uint32) error {
+	if err := p.checkMature(tx, inputIndex); err != nil {
+		return err
+	}
+	return Prepare(tx, inputIndex)
+}
+
+// checkMature checks that the chain state satisfies the lock of the output
+// spent by the input.
+func (p *TimeLock) checkMature(tx *transaction.Transaction, inputIndex uint32) error {
+	kind, value, _, err := Decode(tx.Inputs[inputIndex].SourceTxScript())
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case Absolute:
+		if value < LockTimeThreshold {
+			if value > p.Chain.Height {
+				return ErrImmature
+			}
+		} else if value >= p.Chain.MedianTime {
+			return ErrImmature
+		}
+
+	case Relative:
+		n := value & transaction.SequenceLockTimeMask
+		if value&transaction.SequenceLockTimeIsSeconds != 0 {
+			if uint64(p.Chain.MedianTime) < uint64(p.Chain.SourceMedianTime)+uint64(n)<<9 {
+				return ErrImmature
+			}
+		} else if uint64(p.Chain.Height)+1 < uint64(p.Chain.SourceHeight)+uint64(n) {
+			return ErrImmature
+		}
+	}
+
+	return nil
+}
+
+// Prepare sets the transaction lock time, version and input sequence number
+// needed to satisfy the lock of the output spent by the input, without
+// checking that the output is mature. It is for transactions which are
+// signed before the output matures.
+func Prepare(tx *transaction.Transaction, inputIndex uint32) error {
+	in := tx.Inputs[inputIndex]
+	kind, value, _, err := Decode(in.SourceTxScript())
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case Absolute:
+		if tx.LockTime != 0 && (tx.LockTime < LockTimeThreshold) != (value < LockTimeThreshold) {
+			return ErrLockTimeConflict
+		}
+		if tx.LockTime < value {
+			tx.LockTime = value
+		}
+		if in.SequenceNumber == transaction.MaxTxInSequenceNum {
+			in.SequenceNumber = transaction.MaxTxInSequenceNum - 1
+		}
+
+	case Relative:
+		if tx.Version < 2 {
+			tx.Version = 2
+		}
+		in.SequenceNumber = value
+	}
+
+	return nil
+}
+
+// Prepared reports whether the transaction lock time, version and input
+// sequence number satisfy the lock of the output spent by the input, as
+// OP_CHECKLOCKTIMEVERIFY or OP_CHECKSEQUENCEVERIFY require.
+func Prepared(tx *transaction.Transaction, inputIndex uint32) bool {
+	in := tx.Inputs[inputIndex]
+	kind, value, _, err := Decode(in.SourceTxScript())
+	if err != nil {
+		return false
+	}
+
+	switch kind {
+	case Absolute:
+		return in.SequenceNumber != transaction.MaxTxInSequenceNum &&
+			(tx.LockTime < LockTimeThreshold) == (value < LockTimeThreshold) &&
+			tx.LockTime >= value
+	case Relative:
+		seq := in.SequenceNumber
+		return tx.Version >= 2 && seq&transaction.SequenceLockTimeDisabled == 0 &&
+			seq&transaction.SequenceLockTimeIsSeconds == value&transaction.SequenceLockTimeIsSeconds &&
+			seq&transaction.SequenceLockTimeMask >= value&transaction.SequenceLockTimeMask
+	}
+	return false
+}
+
+// Sign produces the unlocking script <sig> <pubkey>. It does not change the
+// transaction, so it fails with ErrNotPrepared unless Apply or Prepare has
+// been called first.
+func (p *TimeLock) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+	if tx.Inputs[inputIndex].SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+
+	_, _, pkh, err := Decode(tx.Inputs[inputIndex].SourceTxScript())
+	if err != nil {
+		return nil, err
+	}
+	pubKey := p.PrivateKey.PubKey().Compressed()
+	if !bytes.Equal(pkh, crypto.Hash160(pubKey)) {
+		return nil, ErrKeyMismatch
+	}
+
+	if err = p.checkMature(tx, inputIndex); err != nil {
+		return nil, err
+	}
+	if !Prepared(tx, inputIndex) {
+		return nil, ErrNotPrepared
+	}
+
+	sh, err := tx.CalcInputSignatureHash(inputIndex, *p.SigHashFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := p.PrivateKey.Sign(sh)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBuf := make([]byte, 0)
+	sigBuf = append(sigBuf, sig.Serialize()...)
+	sigBuf = append(sigBuf, uint8(*p.SigHashFlag))
+
+	s := &script.Script{}
+	if err = s.AppendPushData(sigBuf); err != nil {
+		return nil, err
+	} else if err = s.AppendPushData(pubKey); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// EstimateLength returns the worst case length of the unlocking script,
+// the same as for P2PKH.
+func (p *TimeLock) EstimateLength(_ *transaction.Transaction, inputIndex uint32) uint32 {
+	return transaction.P2PKHUnlockingScriptLength
+}
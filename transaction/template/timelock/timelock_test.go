@@ -0,0 +1,191 @@
+package timelock_test
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter/scriptflag"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/timelock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSpend(t *testing.T, lockingScript *script.Script, unlocker transaction.UnlockingScriptTemplate) *transaction.Transaction {
+	tx := transaction.NewTransaction()
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+		0,
+		lockingScript.String(),
+		1000,
+		unlocker,
+	))
+	require.NoError(t, tx.PayToAddress("1AdZmoAQUw4XCsCihukoHMvNWXcsd8jDN6", 900))
+	return tx
+}
+
+// execute runs the spend with pre-genesis rules, since CHECKLOCKTIMEVERIFY
+// and CHECKSEQUENCEVERIFY are NOPs after genesis.
+func execute(tx *transaction.Transaction) error {
+	return interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, 0, tx.Inputs[0].SourceTxOutput()),
+		interpreter.WithFlags(scriptflag.VerifyCheckLockTimeVerify|scriptflag.VerifyCheckSequenceVerify),
+		interpreter.WithForkID(),
+	)
+}
+
+func TestLockDecode(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+
+	for _, value := range []uint32{0, 16, 127, 128, 800000, 1700000000, 0xffffffff} {
+		s, err := timelock.LockAbsolute(value, address)
+		require.NoError(t, err)
+
+		kind, decoded, pkh, err := timelock.Decode(s)
+		require.NoError(t, err)
+		require.Equal(t, timelock.Absolute, kind)
+		require.Equal(t, value, decoded)
+		require.Equal(t, []byte(address.PublicKeyHash), pkh)
+	}
+
+	s, err := timelock.LockRelative(timelock.RelativeSeconds(3600), address)
+	require.NoError(t, err)
+	kind, decoded, _, err := timelock.Decode(s)
+	require.NoError(t, err)
+	require.Equal(t, timelock.Relative, kind)
+	require.Equal(t, uint32(transaction.SequenceLockTimeIsSeconds|8), decoded)
+
+	_, err = timelock.LockRelative(transaction.SequenceLockTimeDisabled, address)
+	require.ErrorIs(t, err, timelock.ErrBadSequence)
+
+	p2pkh, err := script.NewFromHex("76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac")
+	require.NoError(t, err)
+	_, _, _, err = timelock.Decode(p2pkh)
+	require.ErrorIs(t, err, timelock.ErrNotTimeLock)
+}
+
+func TestSignAbsolute(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+	lockingScript, err := timelock.LockAbsolute(800000, address)
+	require.NoError(t, err)
+
+	t.Run("immature", func(t *testing.T) {
+		unlocker, err := timelock.Unlock(priv, timelock.ChainState{Height: 799999}, nil)
+		require.NoError(t, err)
+		_, err = unlocker.Sign(newSpend(t, lockingScript, unlocker), 0)
+		require.ErrorIs(t, err, timelock.ErrImmature)
+	})
+
+	t.Run("mature", func(t *testing.T) {
+		unlocker, err := timelock.Unlock(priv, timelock.ChainState{Height: 800000}, nil)
+		require.NoError(t, err)
+		tx := newSpend(t, lockingScript, unlocker)
+		_, err = unlocker.Sign(tx, 0)
+		require.ErrorIs(t, err, timelock.ErrNotPrepared)
+		require.Zero(t, tx.LockTime)
+
+		require.NoError(t, unlocker.Apply(tx, 0))
+		require.NoError(t, tx.Sign())
+		require.Equal(t, uint32(800000), tx.LockTime)
+		require.Less(t, tx.Inputs[0].SequenceNumber, uint32(transaction.MaxTxInSequenceNum))
+		require.LessOrEqual(t, uint32(len(*tx.Inputs[0].UnlockingScript)), unlocker.EstimateLength(tx, 0))
+		require.NoError(t, execute(tx))
+		require.True(t, timelock.Prepared(tx, 0))
+
+		// Lowering the lock time after signing must fail verification.
+		tx.LockTime = 799999
+		require.Error(t, execute(tx))
+		require.False(t, timelock.Prepared(tx, 0))
+	})
+
+	t.Run("conflicting lock time", func(t *testing.T) {
+		unlocker, err := timelock.Unlock(priv, timelock.ChainState{Height: 800000}, nil)
+		require.NoError(t, err)
+		tx := newSpend(t, lockingScript, unlocker)
+		tx.LockTime = 1700000000
+		require.ErrorIs(t, unlocker.Apply(tx, 0), timelock.ErrLockTimeConflict)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		other, err := ec.NewPrivateKey()
+		require.NoError(t, err)
+		unlocker, err := timelock.Unlock(other, timelock.ChainState{Height: 800000}, nil)
+		require.NoError(t, err)
+		_, err = unlocker.Sign(newSpend(t, lockingScript, unlocker), 0)
+		require.ErrorIs(t, err, timelock.ErrKeyMismatch)
+	})
+}
+
+func TestSignRelative(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+	lockingScript, err := timelock.LockRelative(timelock.RelativeBlocks(144), address)
+	require.NoError(t, err)
+
+	unlocker, err := timelock.Unlock(priv, timelock.ChainState{Height: 800100, SourceHeight: 800000}, nil)
+	require.NoError(t, err)
+	_, err = unlocker.Sign(newSpend(t, lockingScript, unlocker), 0)
+	require.ErrorIs(t, err, timelock.ErrImmature)
+
+	unlocker, err = timelock.Unlock(priv, timelock.ChainState{Height: 800143, SourceHeight: 800000}, nil)
+	require.NoError(t, err)
+	tx := newSpend(t, lockingScript, unlocker)
+	tx.Version = 1
+	require.NoError(t, unlocker.Apply(tx, 0))
+	require.NoError(t, tx.Sign())
+	require.Equal(t, uint32(2), tx.Version)
+	require.Equal(t, uint32(144), tx.Inputs[0].SequenceNumber)
+	require.NoError(t, execute(tx))
+	require.True(t, timelock.Prepared(tx, 0))
+
+	tx.Inputs[0].SequenceNumber = 143
+	require.Error(t, execute(tx))
+	require.False(t, timelock.Prepared(tx, 0))
+}
+
+func TestSignAfterOtherInputs(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+	lockingScript, err := timelock.LockAbsolute(800000, address)
+	require.NoError(t, err)
+	p2pkhScript, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	p2pkhUnlocker, err := p2pkh.Unlock(priv, nil)
+	require.NoError(t, err)
+	unlocker, err := timelock.Unlock(priv, timelock.ChainState{Height: 800000}, nil)
+	require.NoError(t, err)
+
+	// The timelock input is signed after the P2PKH input, so preparing it
+	// while signing would invalidate the first signature.
+	tx := newSpend(t, p2pkhScript, p2pkhUnlocker)
+	require.NoError(t, tx.AddInputFrom(
+		"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+		1,
+		lockingScript.String(),
+		1000,
+		unlocker,
+	))
+	require.ErrorIs(t, tx.Sign(), timelock.ErrNotPrepared)
+
+	require.NoError(t, unlocker.Apply(tx, 1))
+	require.NoError(t, tx.Sign())
+	for i := range tx.Inputs {
+		require.NoError(t, interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, i, tx.Inputs[i].SourceTxOutput()),
+			interpreter.WithFlags(scriptflag.VerifyCheckLockTimeVerify|scriptflag.VerifyCheckSequenceVerify),
+			interpreter.WithForkID(),
+		))
+	}
+}
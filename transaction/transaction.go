@@ -434,6 +434,34 @@ func (tx *Transaction) Size() int {
 	return len(tx.Bytes())
 }
 
+// EstimateSize returns the size of tx in bytes once signed. Inputs which
+// already have an unlocking script are measured as they are, and those
+// which only have an UnlockingScriptTemplate are sized using its
+// EstimateLength, so the transaction does not need to be signed first.
+func (tx *Transaction) EstimateSize() (int, error) {
+	size := 4
+	size += VarInt(len(tx.Inputs)).Length()
+	for vin, i := range tx.Inputs {
+		size += 40
+		if i.UnlockingScript != nil && len(*i.UnlockingScript) > 0 {
+			scriptLen := len(*i.UnlockingScript)
+			size += VarInt(scriptLen).Length() + scriptLen
+		} else if i.UnlockingScriptTemplate != nil {
+			scriptLen := int(i.UnlockingScriptTemplate.EstimateLength(tx, uint32(vin)))
+			size += VarInt(scriptLen).Length() + scriptLen
+		} else {
+			return 0, ErrNoUnlockingScript
+		}
+	}
+	size += VarInt(len(tx.Outputs)).Length()
+	for _, o := range tx.Outputs {
+		scriptLen := len(*o.LockingScript)
+		size += 8 + VarInt(scriptLen).Length() + scriptLen
+	}
+	size += 4
+	return size, nil
+}
+
 func (tx *Transaction) AddMerkleProof(bump *MerklePath) error {
 	if !slices.ContainsFunc(bump.Path[0], func(v *PathElement) bool {
 		return v.Hash.Equal(*tx.TxID())
@@ -10,7 +10,14 @@ import (
 	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/bsv-blockchain/go-sdk/transaction"
 	feemodel "github.com/bsv-blockchain/go-sdk/transaction/fee_model"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/hashpuzzle"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/multisig"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/ordlock"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pk"
 	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/pushdrop"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/rpuzzle"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/timelock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -295,6 +302,152 @@ func TestTransactionFee(t *testing.T) {
 	t.Logf("Computed fee: %d satoshis", fee)
 }
 
+func TestEstimateSize(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+	lockingScript, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+	unlocker, err := p2pkh.Unlock(priv, nil)
+	require.NoError(t, err)
+
+	tx := transaction.NewTransaction()
+	for vout := uint32(0); vout < 3; vout++ {
+		require.NoError(t, tx.AddInputFrom(
+			"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+			vout, lockingScript.String(), 1000, unlocker))
+	}
+	require.NoError(t, tx.PayToAddress(address.AddressString, 2000))
+	require.NoError(t, tx.PayToAddress(address.AddressString, 0))
+	tx.Outputs[1].Change = true
+
+	estimated, err := tx.EstimateSize()
+	require.NoError(t, err)
+
+	// The fee is computed from the estimate, so signing once is enough.
+	feeModel := &feemodel.SatoshisPerKilobyte{Satoshis: 50}
+	require.NoError(t, tx.Fee(feeModel, transaction.ChangeDistributionEqual))
+	require.Len(t, tx.Outputs, 2)
+	require.NoError(t, tx.Sign())
+	require.LessOrEqual(t, tx.Size(), estimated)
+	require.GreaterOrEqual(t, tx.Size(), estimated-3*len(tx.Inputs))
+
+	fee, err := tx.GetFee()
+	require.NoError(t, err)
+	expected, err := feeModel.ComputeFee(tx)
+	require.NoError(t, err)
+	require.Equal(t, expected, fee)
+
+	tx.Inputs[0].UnlockingScript = nil
+	tx.Inputs[0].UnlockingScriptTemplate = nil
+	_, err = tx.EstimateSize()
+	require.ErrorIs(t, err, transaction.ErrNoUnlockingScript)
+}
+
+func TestTemplateEstimateLength(t *testing.T) {
+	priv, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	other, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	address, err := script.NewAddressFromPublicKey(priv.PubKey(), true)
+	require.NoError(t, err)
+	payment, err := p2pkh.Lock(address)
+	require.NoError(t, err)
+
+	type spend struct {
+		lock     *script.Script
+		unlocker transaction.UnlockingScriptTemplate
+		prepare  func(tx *transaction.Transaction) error
+	}
+	must := func(s *script.Script, err error) *script.Script {
+		require.NoError(t, err)
+		return s
+	}
+	tests := map[string]func() spend{
+		"p2pkh": func() spend {
+			u, err := p2pkh.Unlock(priv, nil)
+			require.NoError(t, err)
+			return spend{lock: payment, unlocker: u}
+		},
+		"p2pk": func() spend {
+			u, err := p2pk.Unlock(priv, nil)
+			require.NoError(t, err)
+			return spend{lock: must(p2pk.Lock(priv.PubKey())), unlocker: u}
+		},
+		"multisig": func() spend {
+			u, err := multisig.Unlock([]*ec.PrivateKey{priv, other}, nil)
+			require.NoError(t, err)
+			return spend{lock: must(multisig.Lock([]*ec.PublicKey{priv.PubKey(), other.PubKey()}, 2)), unlocker: u}
+		},
+		"rpuzzle": func() spend {
+			// The R value is fixed by k, so use a new one for each attempt.
+			k, err := ec.NewPrivateKey()
+			require.NoError(t, err)
+			value, err := rpuzzle.Value(k.D, rpuzzle.Raw)
+			require.NoError(t, err)
+			u, err := rpuzzle.Unlock(priv, k.D, nil)
+			require.NoError(t, err)
+			return spend{lock: must(rpuzzle.Lock(value, rpuzzle.Raw)), unlocker: u}
+		},
+		"hashpuzzle": func() spend {
+			u, err := hashpuzzle.Unlock(priv, []byte("secret"), nil)
+			require.NoError(t, err)
+			return spend{lock: must(hashpuzzle.LockSecret([]byte("secret"), address)), unlocker: u}
+		},
+		"timelock": func() spend {
+			u, err := timelock.Unlock(priv, timelock.ChainState{Height: 800000}, nil)
+			require.NoError(t, err)
+			return spend{lock: must(timelock.LockAbsolute(800000, address)), unlocker: u, prepare: func(tx *transaction.Transaction) error {
+				return u.Apply(tx, 0)
+			}}
+		},
+		"pushdrop": func() spend {
+			u, err := pushdrop.Unlock(priv, nil)
+			require.NoError(t, err)
+			return spend{lock: must(pushdrop.Lock(priv.PubKey(), [][]byte{[]byte("field")}, pushdrop.LockBefore)), unlocker: u}
+		},
+		"pushdrop pkh": func() spend {
+			u, err := pushdrop.Unlock(priv, nil)
+			require.NoError(t, err)
+			return spend{lock: must(pushdrop.LockPKH(address, [][]byte{[]byte("field")}, pushdrop.LockAfter)), unlocker: u}
+		},
+		"ordlock cancel": func() spend {
+			u, err := ordlock.Cancel(priv, nil)
+			require.NoError(t, err)
+			lock := must(ordlock.Lock(address, &transaction.TransactionOutput{Satoshis: 5000, LockingScript: payment}))
+			return spend{lock: lock, unlocker: u}
+		},
+	}
+
+	for name, newSpend := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Signatures are shorter than the worst case about half the time,
+			// so sign different transactions until one reaches it.
+			for attempt := 0; attempt < 64; attempt++ {
+				s := newSpend()
+				tx := transaction.NewTransaction()
+				require.NoError(t, tx.AddInputFrom(
+					"45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d",
+					0, s.lock.String(), 1000, s.unlocker))
+				require.NoError(t, tx.PayToAddress(address.AddressString, 900-uint64(attempt)))
+				if s.prepare != nil {
+					require.NoError(t, s.prepare(tx))
+				}
+
+				estimate := int(s.unlocker.EstimateLength(tx, 0))
+				require.NoError(t, tx.Sign())
+				actual := len(*tx.Inputs[0].UnlockingScript)
+				require.LessOrEqual(t, actual, estimate)
+				if actual == estimate {
+					return
+				}
+			}
+			t.Fatal("estimate is never reached by a signed unlocking script")
+		})
+	}
+}
+
 func TestAtomicBEEF(t *testing.T) {
 	// First decode the BEEF data to get a transaction
 	beefBytes, err := hex.DecodeString(BRC62Hex)